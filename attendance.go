@@ -0,0 +1,144 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	iris "github.com/kataras/iris/v12"
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultAttendanceDays is how many days of attendance are listed when no from
+// date is given.
+const defaultAttendanceDays = 7
+
+// attendanceGet handles the GET route for attendance.
+// it optionally takes parameters of "from" and "to" as dates, defaulting to the
+// last week, and "event_id".
+// returns the attendance of events in JSON format
+func attendanceGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+
+	fromStr := ctx.URLParam("from")
+	toStr := ctx.URLParam("to")
+	eventID := strings.TrimSpace(ctx.URLParam("event_id"))
+
+	logger.WithFields(logrus.Fields{
+		"from":     fromStr,
+		"to":       toStr,
+		"event_id": eventID,
+	}).Info("Get attendance.")
+
+	to, err := parseDateParam(toStr)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid to date.")
+		return
+	}
+	if to == nil {
+		now := time.Now().In(getLocation())
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		to = &today
+	}
+
+	from, err := parseDateParam(fromStr)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid from date.")
+		return
+	}
+	if from == nil {
+		start := to.AddDate(0, 0, 1-defaultAttendanceDays) // to is inclusive.
+		from = &start
+	}
+	if to.Before(*from) {
+		httperr.Write(ctx, http.StatusBadRequest, "From must not be after to.")
+		return
+	}
+
+	attendance, err := getCCBService().ListAttendance(ctx.Request().Context(), ccb.ListAttendanceRequest{
+		From:    *from,
+		To:      *to,
+		EventID: eventID,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to list attendance.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to list attendance.")
+		return
+	}
+
+	writeJSON(ctx, map[string]interface{}{"attendance": attendance})
+}
+
+// attendancePostRequest is the JSON body of a request to record attendance.
+type attendancePostRequest struct {
+	EventID       string   `json:"event_id"`
+	Occurrence    string   `json:"occurrence"` // RFC 3339, or 2006-01-02T15:04 in the church time zone.
+	DidNotMeet    bool     `json:"did_not_meet"`
+	HeadCount     *int     `json:"head_count"`
+	IndividualIDs []string `json:"individual_ids"`
+	Topic         string   `json:"topic"`
+	Notes         string   `json:"notes"`
+}
+
+// attendancePost handles the POST route for recording the attendance of an
+// occurrence of an event. It takes a JSON body of attendancePostRequest.
+func attendancePost(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+
+	var req attendancePostRequest
+	if err := ctx.ReadJSON(&req); err != nil || strings.TrimSpace(req.EventID) == "" {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+	occurrence, err := parseOccurrence(req.Occurrence)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid occurrence.")
+		return
+	}
+	if req.HeadCount != nil && *req.HeadCount < 0 {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid head count.")
+		return
+	}
+	ids := []string{}
+	for _, id := range req.IndividualIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if req.DidNotMeet && (len(ids) > 0 || (req.HeadCount != nil && *req.HeadCount > 0)) {
+		httperr.Write(ctx, http.StatusBadRequest, "Events which did not meet cannot have attendees.")
+		return
+	}
+
+	if err := getCCBService().RecordAttendance(ctx.Request().Context(), ccb.RecordAttendanceRequest{
+		EventID:       strings.TrimSpace(req.EventID),
+		Occurrence:    occurrence,
+		DidNotMeet:    req.DidNotMeet,
+		HeadCount:     req.HeadCount,
+		IndividualIDs: ids,
+		Topic:         strings.TrimSpace(req.Topic),
+		Notes:         strings.TrimSpace(req.Notes),
+	}); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"event_id":   req.EventID,
+			"occurrence": req.Occurrence,
+		}).Error("Failed to record attendance.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to record attendance.")
+		return
+	}
+
+	ctx.StatusCode(http.StatusNoContent)
+}
+
+// parseOccurrence parses the start of an occurrence of an event as RFC 3339, or
+// as the value of a datetime-local input in the church time zone, so it can be
+// posted straight from a web form.
+func parseOccurrence(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02T15:04", s, getLocation())
+}
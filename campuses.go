@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	iris "github.com/kataras/iris/v12"
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+)
+
+var (
+	errUnknownForm   = errors.New("invalid form name")
+	errUnknownCampus = errors.New("invalid campus")
+)
+
+// campusesGet handles the GET route for campuses.
+// returns every campus in CCB in JSON format
+func campusesGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+
+	campuses, err := getCCBService().ListCampuses(ctx.Request().Context())
+	if err != nil {
+		logger.WithError(err).Error("Failed to list campuses.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to list campuses.")
+		return
+	}
+
+	out, err := json.Marshal(map[string]interface{}{"campuses": campuses})
+	if err != nil {
+		logger.WithError(err).Error("Failed to marshal campuses.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to marshal campuses.")
+		return
+	}
+
+	ctx.StatusCode(http.StatusOK)
+	ctx.ContentType("application/json")
+	ctx.Write(out)
+}
+
+// resolveForms returns the forms of the type for the campus slug, or for every
+// campus if campus is empty. Types with a campus suffix such as connect_card_jdd
+// are still accepted for existing integrations.
+func resolveForms(typ, campus string) (ccb.Forms, error) {
+	forms := getForms()
+	campus = strings.ToLower(strings.TrimSpace(campus))
+
+	found := forms.Find(typ, campus)
+	if len(found) == 0 && campus == "" {
+		for _, c := range forms.Campuses() {
+			if strings.HasSuffix(typ, "_"+c) {
+				found = forms.Find(strings.TrimSuffix(typ, "_"+c), c)
+				break
+			}
+		}
+	}
+	if len(found) > 0 {
+		return found, nil
+	}
+
+	if campus != "" && len(forms.Find(typ, "")) > 0 {
+		return nil, errUnknownCampus
+	}
+	return nil, errUnknownForm
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	iris "github.com/kataras/iris/v12"
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/ical"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultEventsDays is how many days of events are listed as JSON when no to date
+	// is given.
+	defaultEventsDays = 90
+
+	// pastEventsDays and futureEventsDays are how many days before and after today
+	// public events are fetched from CCB for. Every request is answered from this
+	// one window, so it is cached once whatever dates are asked for.
+	pastEventsDays   = 30
+	futureEventsDays = 365
+)
+
+// publicEvent is an occurrence of an event on the website calendar.
+type publicEvent struct {
+	ccb.EventOccurrence
+	Series string `json:"series"`           // The same for every occurrence of a recurring event.
+	Campus string `json:"campus,omitempty"` // Empty for events of the whole church.
+}
+
+// publicEventsGet handles the public GET route for the events calendar.
+// it optionally takes parameters of "from" and "to" as dates, and "campus".
+// returns the occurrences of public events in JSON format
+func publicEventsGet(ctx iris.Context) {
+	first, _ := publicEventsWindow()
+	today := first.AddDate(0, 0, pastEventsDays)
+	events, ok := listPublicEventsParams(ctx, today, defaultEventsDays)
+	if !ok {
+		return
+	}
+
+	writeJSON(ctx, map[string]interface{}{"events": events})
+}
+
+// publicEventsICS handles the public GET route for the events calendar as an
+// iCalendar feed, which calendar apps can subscribe to.
+// it takes the same parameters as publicEventsGet, but defaults to every event
+// fetched, so recent occurrences stay in calendar apps.
+// occurrences of the same event are written as one recurring event
+func publicEventsICS(ctx iris.Context) {
+	first, _ := publicEventsWindow()
+	events, ok := listPublicEventsParams(ctx, first, pastEventsDays+futureEventsDays+1)
+	if !ok {
+		return
+	}
+
+	name := os.Getenv("PUBLIC_CALENDAR_NAME")
+	if name == "" {
+		name = "Events"
+	}
+	cal := ical.Calendar{
+		ProdID: "-//Vous Church//ccb-webflow-api//EN",
+		Name:   name,
+		Stamp:  time.Now(),
+	}
+
+	// Events are sorted by start, so the first occurrence of each series comes first.
+	series := map[string]int{}
+	for _, e := range events {
+		if i, ok := series[e.Series]; ok {
+			cal.Events[i].RDates = append(cal.Events[i].RDates, e.Start)
+			continue
+		}
+		series[e.Series] = len(cal.Events)
+		ev := ical.Event{
+			UID:         e.Series + "@ccb-webflow-api",
+			Start:       e.Start,
+			End:         e.End,
+			Summary:     e.Name,
+			Description: e.Description,
+			Location:    e.Location,
+		}
+		if e.Type != "" {
+			ev.Categories = []string{e.Type}
+		}
+		cal.Events = append(cal.Events, ev)
+	}
+
+	ctx.ContentType(ical.ContentType)
+	if err := ical.Write(ctx, cal); err != nil {
+		vouslog.GetLogger(ctx.Request().Context()).WithError(err).Error("Failed to write calendar.")
+	}
+}
+
+// listPublicEventsParams lists the public events for the query parameters of
+// publicEventsGet, sorted by start. Without a from date events are listed from
+// defaultFrom, and without a to date for defaultDays, up to the last day fetched.
+// If it returns false, an error has been written.
+func listPublicEventsParams(ctx iris.Context, defaultFrom time.Time, defaultDays int) ([]publicEvent, bool) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+
+	fromStr := ctx.URLParam("from")
+	toStr := ctx.URLParam("to")
+	campus := strings.TrimSpace(ctx.URLParam("campus"))
+
+	logger.WithFields(logrus.Fields{
+		"from":   fromStr,
+		"to":     toStr,
+		"campus": campus,
+	}).Info("Get public events.")
+
+	from, err := parseDateParam(fromStr)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid from date.")
+		return nil, false
+	}
+	if from == nil {
+		from = &defaultFrom
+	}
+
+	first, last := publicEventsWindow()
+	to, err := parseDateParam(toStr)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid to date.")
+		return nil, false
+	}
+	if to == nil {
+		end := from.AddDate(0, 0, defaultDays-1) // to is inclusive.
+		if end.After(last) {
+			end = last
+		}
+		to = &end
+	}
+	if to.Before(*from) {
+		httperr.Write(ctx, http.StatusBadRequest, "From must not be after to.")
+		return nil, false
+	}
+	if from.Before(first) || to.After(last) {
+		httperr.Write(ctx, http.StatusBadRequest, "Events are only listed from "+strconv.Itoa(pastEventsDays)+" days ago to "+strconv.Itoa(futureEventsDays)+" days from today.")
+		return nil, false
+	}
+
+	all, err := listPublicEvents(ctx.Request().Context(), first, last)
+	if err != nil {
+		logger.WithError(err).Error("Failed to list public events.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to list events.")
+		return nil, false
+	}
+
+	// Events of the whole church are shown for every campus.
+	end := to.AddDate(0, 0, 1) // to is inclusive.
+	events := []publicEvent{}
+	for _, e := range all {
+		if e.Start.Before(*from) || !e.Start.Before(end) {
+			continue
+		}
+		if campus == "" || e.Campus == "" || strings.EqualFold(e.Campus, campus) {
+			events = append(events, e)
+		}
+	}
+	return events, true
+}
+
+// publicEventsWindow returns the first and last day public events are fetched
+// from CCB for, from pastEventsDays before today to futureEventsDays after.
+func publicEventsWindow() (first, last time.Time) {
+	now := time.Now().In(getLocation())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return today.AddDate(0, 0, -pastEventsDays), today.AddDate(0, 0, futureEventsDays)
+}
+
+// listPublicEvents returns the public events between the dates first and last,
+// both inclusive, sorted by start. Events are cached, as calendar apps poll the
+// feed, and are always listed for the window of publicEventsWindow so the cache
+// holds one list a day however requests pick their dates.
+func listPublicEvents(ctx context.Context, first, last time.Time) ([]publicEvent, error) {
+	key := "events|" + first.Format("2006-01-02") + "|" + last.Format("2006-01-02")
+	if cached, ok := getPublicEventsCache().Get(key); ok {
+		return cached.([]publicEvent), nil
+	}
+
+	occurrences, err := getCCBService().ListPublicEvents(ctx, first, last)
+	if err != nil {
+		return nil, err
+	}
+	campuses, err := groupCampuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]publicEvent, len(occurrences))
+	for i, o := range occurrences {
+		events[i] = publicEvent{
+			EventOccurrence: o,
+			Series:          eventSeries(o),
+			Campus:          campuses[strings.ToLower(o.GroupName)],
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+
+	getPublicEventsCache().Set(key, events)
+	return events, nil
+}
+
+// groupCampusesCacheKey is the key the campuses of groups are cached under.
+const groupCampusesCacheKey = "group_campuses"
+
+// groupCampuses returns the campus slug of every group by lower case name, as
+// events in CCB belong to a group and the public calendar only names the group.
+func groupCampuses(ctx context.Context) (map[string]string, error) {
+	if cached, ok := getPublicEventsCache().Get(groupCampusesCacheKey); ok {
+		return cached.(map[string]string), nil
+	}
+
+	campuses := map[string]string{}
+	if err := ccb.EachGroup(ctx, getCCBService(), ccb.ListGroupsRequest{}, func(g ccb.Group) error {
+		if g.Campus != "" {
+			campuses[strings.ToLower(strings.TrimSpace(g.Name))] = g.Campus
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	getPublicEventsCache().Set(groupCampusesCacheKey, campuses)
+	return campuses, nil
+}
+
+// eventSeries identifies the event an occurrence is of. The public calendar does
+// not always include the event id, so occurrences of the same event are otherwise
+// recognised by having the same name, group, location, time of day and length.
+func eventSeries(o ccb.EventOccurrence) string {
+	if o.EventID != "" {
+		return "event-" + o.EventID
+	}
+	h := sha1.New()
+	for _, s := range []string{
+		o.Name,
+		o.GroupName,
+		o.Location,
+		o.Start.Format("15:04"),
+		o.End.Sub(o.Start).String(),
+	} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return "event-" + hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// eventGet handles the GET route for a single event.
+func eventGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	id := ctx.Params().Get("id")
+
+	event, err := getCCBService().GetEvent(ctx.Request().Context(), id)
+	if err == ccb.ErrNotFound {
+		httperr.Write(ctx, http.StatusNotFound, "Event not found.")
+		return
+	} else if err != nil {
+		logger.WithError(err).WithField("event_id", id).Error("Failed to get event.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to get event.")
+		return
+	}
+
+	writeJSON(ctx, event)
+}
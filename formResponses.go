@@ -1,34 +1,46 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	iris "github.com/kataras/iris/v12"
-	"github.com/kelseyhightower/envconfig"
 	"github.com/mruVOUS/ccb-webflow-api/lib/ccb"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
 	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
 	"github.com/sirupsen/logrus"
 )
 
-// formNameMap maps the query parameter values to the CCB form IDs
-// this is used in building the request to CCB for form responses
-var formNameMap = map[string]ccb.FormID{
-	"connect_card_itech":         ccb.FormIDConnectCardITech,
-	"connect_card_jdd":           ccb.FormIDConnectCardJDD,
-	"growth_track_sign_up_itech": ccb.FormIDGrowthTrackSignUpITech,
-	"growth_track_sign_up_jdd":   ccb.FormIDGrowthTrackSignUpJDD,
+// formResponsesResponse is the JSON body returned by formResponsesGet.
+type formResponsesResponse struct {
+	Responses []ccb.FormResponse `json:"responses"`
+	Scanned   int                `json:"scanned"` // Number of form responses fetched from CCB before filtering.
+
+	// HighWaterMark is the latest modified time of the responses, to pass as
+	// modified_since on the next poll. modified_since is inclusive, so the latest
+	// response is returned again by the next poll. Left out if only the first page
+	// was fetched and more responses may have been left out.
+	HighWaterMark *time.Time `json:"high_water_mark,omitempty"`
 }
 
 // formResponsesGet handles the GET route for form responses.
-// it takes a parameter of a form name, and optionally takes a parameter of "modified_since"
+// it takes a parameter of a form type, and optionally takes a parameter of "campus",
+// a parameter of "modified_since"
+// as a date or RFC3339 timestamp, filter parameters (see parseFormResponseFilter),
+// and "expand=individual" to embed the profile of the individual who responded.
 // returns form responses in JSON format
 func formResponsesGet(ctx iris.Context) {
 	logger := vouslog.GetLogger(ctx.Request().Context())
 
 	// Parse query parameters.
 	formName := ctx.Params().Get("type")
+	campus := ctx.URLParam("campus")
 	modifiedSinceStr := ctx.URLParam("modified_since")
 
 	// Define defaults.
@@ -43,6 +55,7 @@ func formResponsesGet(ctx iris.Context) {
 
 	logger.WithFields(logrus.Fields{
 		"type":           formName,
+		"campus":         campus,
 		"modified_since": modifiedSinceStr,
 		"page":           page,
 		"page_size":      pageSize,
@@ -50,51 +63,99 @@ func formResponsesGet(ctx iris.Context) {
 
 	// if no form name given, return error
 	if formName == "" {
-		ctx.StatusCode(http.StatusBadRequest)
-		ctx.WriteString("Error: No form name provided")
+		httperr.Write(ctx, http.StatusBadRequest, "No form name provided.")
 		return
 	}
 
-	formID, ok := formNameMap[formName]
-	if !ok {
-		ctx.StatusCode(http.StatusBadRequest)
-		ctx.WriteString("Error: Invalid form name.")
-		// TODO: Write an error payload about the bad request.
+	forms, err := resolveForms(formName, campus)
+	if err == errUnknownCampus {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid campus.")
+		return
+	} else if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid form name.")
 		return
 	}
 
-	var modifiedSince *time.Time
-	if modifiedSinceStr != "" {
-		modTime, err := time.Parse("2006-01-02", modifiedSinceStr)
-		if err != nil {
-			logger.WithField("modified_since", modifiedSinceStr).Error("Failed to parse modified since.")
-			ctx.StatusCode(http.StatusBadRequest)
-			ctx.WriteString("Error: Invalid modified since date.")
-			// TODO: Write an error payload about the bad request.
-			return
-		}
-		modifiedSince = &modTime
-	}
-
-	svcConfig := ccb.Config{}
-	envconfig.MustProcess("", &svcConfig)
-	svc := ccb.New(svcConfig)
-	resp, err := svc.GetFormResponses(ctx.Request().Context(), ccb.GetFormResponsesRequest{
-		FormID:        formID,
-		ModifiedSince: modifiedSince,
-		Page:          1,
-		PageSize:      10,
-		// TODO: Pass in paging parameters.
-	})
+	modifiedSince, precise, err := parseModifiedSince(modifiedSinceStr)
+	if err != nil {
+		logger.WithField("modified_since", modifiedSinceStr).Error("Failed to parse modified since.")
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid modified since date.")
+		return
+	}
+
+	filter, err := parseFormResponseFilter(ctx.Request().URL.Query())
 	if err != nil {
-		ctx.StatusCode(http.StatusInternalServerError)
+		logger.WithError(err).Error("Failed to parse filter.")
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid filter: "+err.Error())
 		return
 	}
+	if precise {
+		filter.ModifiedSince = modifiedSince
+	}
+
+	// Responses for every campus are concatenated, one form after the other.
+	var resp formResponsesResponse
+	var truncated bool
+	for _, form := range forms {
+		req := ccb.GetFormResponsesRequest{
+			FormID:        form.ID,
+			ModifiedSince: modifiedSince,
+			Page:          1,
+			PageSize:      10,
+			// TODO: Pass in paging parameters.
+		}
+
+		if filter.IsEmpty() && modifiedSince == nil {
+			pageResp, err := getCCBService().GetFormResponses(ctx.Request().Context(), req)
+			if err != nil {
+				logger.WithError(err).Error("Failed to get form responses.")
+				httperr.Write(ctx, http.StatusInternalServerError, "Failed to get form responses.")
+				return
+			}
+			resp.Responses = append(resp.Responses, pageResp.Responses...)
+			resp.Scanned += len(pageResp.Responses)
+			if len(pageResp.Responses) >= req.PageSize {
+				truncated = true
+			}
+		} else {
+			// Filters apply across every page, and polls need every response for
+			// the high water mark, so fetch them all.
+			req.PageSize = ccb.DefaultPageSize
+			filterResp, err := ccb.FilterFormResponses(ctx.Request().Context(), getCCBService(), req, filter)
+			if err != nil {
+				logger.WithError(err).Error("Failed to filter form responses.")
+				httperr.Write(ctx, http.StatusInternalServerError, "Failed to get form responses.")
+				return
+			}
+			resp.Responses = append(resp.Responses, filterResp.Responses...)
+			resp.Scanned += filterResp.Scanned
+		}
+	}
+	if resp.Responses == nil {
+		resp.Responses = []ccb.FormResponse{}
+	}
+	if !truncated {
+		resp.HighWaterMark = highWaterMark(resp.Responses, modifiedSince)
+	}
+
+	if hasExpand(ctx, "individual") {
+		// CCB has no batch lookup, so each individual is a call against the quota.
+		if len(individualIDs(resp.Responses)) > maxExpandIndividuals {
+			httperr.Write(ctx, http.StatusBadRequest, "Too many individuals to expand, at most "+strconv.Itoa(maxExpandIndividuals)+". Narrow the filters.")
+			return
+		}
+		if err := expandIndividuals(ctx.Request().Context(), resp.Responses); err != nil {
+			logger.WithError(err).Error("Failed to expand individuals.")
+			httperr.Write(ctx, http.StatusInternalServerError, "Failed to get individuals.")
+			return
+		}
+	}
 
 	// TODO: Probably need to implement the next page trick?
-	out, err := json.Marshal(resp.Responses)
+	out, err := json.Marshal(resp)
 	if err != nil {
-		ctx.StatusCode(http.StatusInternalServerError)
+		logger.WithError(err).Error("Failed to marshal form responses.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to marshal form responses.")
 		return
 	}
 
@@ -103,3 +164,136 @@ func formResponsesGet(ctx iris.Context) {
 	ctx.Write(out)
 	return
 }
+
+// maxExpandIndividuals is the most individuals which can be expanded in one
+// request, as each is fetched from CCB on its own.
+const maxExpandIndividuals = 50
+
+// individualIDs returns the unique ids of the individuals linked to the responses.
+func individualIDs(responses []ccb.FormResponse) []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, r := range responses {
+		if r.Individual != nil && r.Individual.ID != "" && !seen[r.Individual.ID] {
+			seen[r.Individual.ID] = true
+			ids = append(ids, r.Individual.ID)
+		}
+	}
+	return ids
+}
+
+// expandIndividuals fetches the profiles of the individuals linked to the
+// responses and embeds them.
+func expandIndividuals(ctx context.Context, responses []ccb.FormResponse) error {
+	ids := individualIDs(responses)
+	if len(ids) == 0 {
+		return nil
+	}
+
+	individuals, err := ccb.GetIndividuals(ctx, getCCBService(), ids)
+	if err != nil {
+		return err
+	}
+	for _, r := range responses {
+		if r.Individual != nil {
+			r.Individual.Profile = individuals[r.Individual.ID]
+		}
+	}
+	return nil
+}
+
+// highWaterMark returns the latest modified time of the responses, or since if
+// there are none.
+func highWaterMark(responses []ccb.FormResponse, since *time.Time) *time.Time {
+	mark := since
+	for _, r := range responses {
+		if r.Modified != nil && (mark == nil || r.Modified.After(*mark)) {
+			mark = r.Modified
+		}
+	}
+	return mark
+}
+
+// parseModifiedSince parses the optional modified_since query parameter, either
+// as a date or as an RFC3339 timestamp. precise is true for timestamps, which need
+// filtering after fetching as CCB only filters by day.
+// Returns nil if not set.
+func parseModifiedSince(s string) (modifiedSince *time.Time, precise bool, err error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t, true, nil
+	}
+	modifiedSince, err = parseDateParam(s)
+	return modifiedSince, false, err
+}
+
+// parseDateParam parses an optional date query parameter as midnight in the
+// church time zone. Returns nil if not set.
+func parseDateParam(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, getLocation())
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// parseFormResponseFilter parses the filter query parameters for form responses:
+//
+//	answer[<question>]=<answer>    e.g. answer[Campus]=JDD
+//	profile[<field>]=<value>       e.g. profile[email]=jane@example.com
+//	created_after, created_before, modified_before as dates.
+//	payment_status=<status>,...    e.g. payment_status=unpaid,partial
+func parseFormResponseFilter(q url.Values) (ccb.FormResponseFilter, error) {
+	filter := ccb.FormResponseFilter{
+		Answers: map[string]string{},
+		Profile: map[string]string{},
+	}
+
+	for key, values := range q {
+		if name, ok := bracketParam(key, "answer"); ok {
+			filter.Answers[name] = values[0]
+		} else if name, ok := bracketParam(key, "profile"); ok {
+			filter.Profile[name] = values[0]
+		}
+	}
+
+	dates := []struct {
+		param string
+		dst   **time.Time
+	}{
+		{"created_after", &filter.CreatedAfter},
+		{"created_before", &filter.CreatedBefore},
+		{"modified_before", &filter.ModifiedBefore},
+	}
+	for _, d := range dates {
+		t, err := parseDateParam(q.Get(d.param))
+		if err != nil {
+			return filter, errors.New("invalid " + d.param)
+		}
+		*d.dst = t
+	}
+
+	if s := q.Get("payment_status"); s != "" {
+		for _, status := range strings.Split(s, ",") {
+			switch status := ccb.PaymentStatus(strings.ToLower(strings.TrimSpace(status))); status {
+			case ccb.PaymentStatusPaid, ccb.PaymentStatusPartial, ccb.PaymentStatusUnpaid, ccb.PaymentStatusRefunded:
+				filter.PaymentStatus = append(filter.PaymentStatus, status)
+			default:
+				return filter, errors.New("invalid payment_status")
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+// bracketParam returns the name from a query parameter key of the form prefix[name].
+func bracketParam(key, prefix string) (string, bool) {
+	if !strings.HasPrefix(key, prefix+"[") || !strings.HasSuffix(key, "]") {
+		return "", false
+	}
+	name := key[len(prefix)+1 : len(key)-1]
+	return name, name != ""
+}
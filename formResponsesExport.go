@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	iris "github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb"
+	"github.com/mruVOUS/ccb-webflow-api/lib/export"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// formResponsesExport handles the GET route for exporting form responses.
+// it takes a parameter of a form type, and optionally takes parameters of "campus",
+// "format" (csv or ndjson) and "modified_since".
+// streams every form response from CCB as a file download.
+func formResponsesExport(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+
+	// Parse query parameters.
+	formName := ctx.Params().Get("type")
+	campus := ctx.URLParam("campus")
+	formatStr := ctx.URLParam("format")
+	modifiedSinceStr := ctx.URLParam("modified_since")
+
+	logger.WithFields(logrus.Fields{
+		"type":           formName,
+		"campus":         campus,
+		"format":         formatStr,
+		"modified_since": modifiedSinceStr,
+	}).Info("Export form responses.")
+
+	forms, err := resolveForms(formName, campus)
+	if err == errUnknownCampus {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid campus.")
+		return
+	} else if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid form name.")
+		return
+	}
+
+	format, err := export.ParseFormat(formatStr)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid format, must be csv or ndjson.")
+		return
+	}
+
+	// Exports are day-granular, timestamps are truncated to the day by CCB.
+	modifiedSince, _, err := parseModifiedSince(modifiedSinceStr)
+	if err != nil {
+		logger.WithField("modified_since", modifiedSinceStr).Error("Failed to parse modified since.")
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid modified since date.")
+		return
+	}
+
+	reqs := make([]ccb.GetFormResponsesRequest, 0, len(forms))
+	for _, form := range forms {
+		reqs = append(reqs, ccb.GetFormResponsesRequest{
+			FormID:        form.ID,
+			ModifiedSince: modifiedSince,
+		})
+	}
+
+	if campus != "" {
+		formName += "-" + campus
+	}
+	filename := formName + "-" + time.Now().In(getLocation()).Format("2006-01-02") + "." + string(format)
+	// The server write timeout is too short to page through every response, so
+	// exports have their own.
+	deadline := time.Now().Add(getExportConfig().WriteTimeout)
+	if err := extendWriteDeadline(ctx.Request(), deadline); err != nil {
+		logger.WithError(err).Warn("Failed to set the export write deadline.")
+	}
+
+	ctx.ContentType(format.ContentType())
+	ctx.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	if err := export.FormResponses(ctx.Request().Context(), getCCBService(), reqs, format, ctx.ResponseWriter()); err != nil {
+		logger.WithError(err).Error("Failed to export form responses.")
+
+		// The status can only be changed if nothing has been streamed yet.
+		if ctx.ResponseWriter().Written() == context.NoWritten {
+			ctx.ResponseWriter().Header().Del("Content-Disposition")
+			httperr.Write(ctx, http.StatusInternalServerError, "Failed to export form responses.")
+		}
+	}
+}
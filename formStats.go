@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	iris "github.com/kataras/iris/v12"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/stats"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultStatsDays is the number of days reported when from is not set.
+const defaultStatsDays = 12 * 7
+
+// maxStatsDays is the longest period reported for each interval, as every response
+// in it is fetched from CCB and counted in a period of the report.
+var maxStatsDays = map[stats.Interval]int{
+	stats.IntervalDay:   92,
+	stats.IntervalWeek:  366,
+	stats.IntervalMonth: 3 * 366,
+}
+
+// formStatsGet handles the GET route for form response statistics.
+// it takes a parameter of a form type, and optionally takes parameters of "campus",
+// "from" and "to" as inclusive dates, and "interval" (day, week or month).
+// returns the counts per interval and campus, and the answer distribution of
+// multiple choice questions in JSON format
+func formStatsGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+
+	// Parse query parameters.
+	formName := ctx.Params().Get("slug")
+	campus := ctx.URLParam("campus")
+	fromStr := ctx.URLParam("from")
+	toStr := ctx.URLParam("to")
+	intervalStr := ctx.URLParam("interval")
+
+	logger.WithFields(logrus.Fields{
+		"type":     formName,
+		"campus":   campus,
+		"from":     fromStr,
+		"to":       toStr,
+		"interval": intervalStr,
+	}).Info("Get form stats.")
+
+	forms, err := resolveForms(formName, campus)
+	if err == errUnknownCampus {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid campus.")
+		return
+	} else if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid form name.")
+		return
+	}
+
+	interval, err := stats.ParseInterval(intervalStr)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid interval, must be day, week or month.")
+		return
+	}
+
+	to, err := parseDateParam(toStr)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid to date.")
+		return
+	}
+	if to == nil {
+		now := time.Now().In(getLocation())
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		to = &today
+	}
+	end := to.AddDate(0, 0, 1) // to is inclusive.
+
+	from, err := parseDateParam(fromStr)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid from date.")
+		return
+	}
+	if from == nil {
+		start := end.AddDate(0, 0, -defaultStatsDays) // Days rather than hours, which shift across DST changes.
+		from = &start
+	}
+	if !from.Before(end) {
+		httperr.Write(ctx, http.StatusBadRequest, "From must not be after to.")
+		return
+	}
+	if maxDays := maxStatsDays[interval]; end.After(from.AddDate(0, 0, maxDays)) {
+		httperr.Write(ctx, http.StatusBadRequest, "The period must not be longer than "+strconv.Itoa(maxDays)+" days for the "+string(interval)+" interval.")
+		return
+	}
+
+	req := stats.Request{
+		From:     *from,
+		To:       end,
+		Interval: interval,
+	}
+	for _, form := range forms {
+		req.FormIDs = append(req.FormIDs, form.ID)
+	}
+
+	key := strings.Join([]string{formName, campus, req.From.Format("2006-01-02"), req.To.Format("2006-01-02"), string(interval)}, "|")
+	report, ok := getStatsCache().Get(key)
+	if !ok {
+		report, err = stats.FormResponses(ctx.Request().Context(), getCCBService(), req)
+		if err != nil {
+			logger.WithError(err).Error("Failed to compute form stats.")
+			httperr.Write(ctx, http.StatusInternalServerError, "Failed to get form stats.")
+			return
+		}
+		getStatsCache().Set(key, report)
+	}
+
+	out, err := json.Marshal(report)
+	if err != nil {
+		logger.WithError(err).Error("Failed to marshal form stats.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to marshal form stats.")
+		return
+	}
+
+	ctx.StatusCode(http.StatusOK)
+	ctx.ContentType("application/json")
+	ctx.Write(out)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	iris "github.com/kataras/iris/v12"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/stats"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// maxGivingDays is the longest period the giving summary covers, as every batch
+// in it is fetched from CCB.
+const maxGivingDays = 366
+
+// givingSummaryGet handles the GET route for the giving summary, which is only
+// served to the finance user.
+// it optionally takes parameters of "from" and "to" as inclusive dates, defaulting
+// to the last 12 weeks, and "campus".
+// returns the totals by fund and by week in JSON format, without who gave
+func givingSummaryGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+
+	campus := strings.TrimSpace(ctx.URLParam("campus"))
+	fromStr := ctx.URLParam("from")
+	toStr := ctx.URLParam("to")
+
+	logger.WithFields(logrus.Fields{
+		"campus": campus,
+		"from":   fromStr,
+		"to":     toStr,
+	}).Info("Get giving summary.")
+
+	to, err := parseDateParam(toStr)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid to date.")
+		return
+	}
+	if to == nil {
+		now := time.Now().In(getLocation())
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		to = &today
+	}
+	end := to.AddDate(0, 0, 1) // to is inclusive.
+
+	from, err := parseDateParam(fromStr)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid from date.")
+		return
+	}
+	if from == nil {
+		start := end.AddDate(0, 0, -defaultStatsDays)
+		from = &start
+	}
+	if !from.Before(end) {
+		httperr.Write(ctx, http.StatusBadRequest, "From must not be after to.")
+		return
+	}
+	if end.After(from.AddDate(0, 0, maxGivingDays)) {
+		httperr.Write(ctx, http.StatusBadRequest, "The period must not be longer than 366 days.")
+		return
+	}
+
+	req := stats.GivingRequest{
+		From:   *from,
+		To:     end,
+		Campus: campus,
+	}
+
+	key := strings.Join([]string{"giving", strings.ToLower(campus), req.From.Format("2006-01-02"), req.To.Format("2006-01-02")}, "|")
+	report, ok := getStatsCache().Get(key)
+	if !ok {
+		report, err = stats.Giving(ctx.Request().Context(), getCCBService(), req)
+		if err != nil {
+			logger.WithError(err).Error("Failed to compute giving summary.")
+			httperr.Write(ctx, http.StatusInternalServerError, "Failed to get giving summary.")
+			return
+		}
+		getStatsCache().Set(key, report)
+	}
+
+	writeJSON(ctx, report)
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	iris "github.com/kataras/iris/v12"
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/notify"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// maxJoinMessageLength is the longest message a visitor can send a group leader.
+const maxJoinMessageLength = 2000
+
+// groupJoinRequest is the JSON body of a request to join a group from the website.
+type groupJoinRequest struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+	Phone     string `json:"phone"`
+	Message   string `json:"message"` // Optional message to the group leader.
+
+	CaptchaToken string `json:"captcha_token"` // Token of the CAPTCHA solved on the website.
+}
+
+// publicGroupJoin handles the public POST route for requesting to join a group.
+// it takes a parameter of a group id and a JSON body of groupJoinRequest.
+// once the CAPTCHA is verified, the visitor is matched to an individual in CCB, or
+// one is created, and added to the group as requesting to join. The group leader is
+// then notified in the background.
+func publicGroupJoin(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	groupID := ctx.Params().Get("id")
+
+	var req groupJoinRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+
+	details, ok := parseIndividualDetails(ctx, req.FirstName, req.LastName, req.Email, req.Phone)
+	if !ok {
+		return
+	}
+	message := strings.TrimSpace(req.Message)
+	if len(message) > maxJoinMessageLength {
+		httperr.Write(ctx, http.StatusBadRequest, "Message is too long.")
+		return
+	}
+
+	logger = logger.WithField("group_id", groupID)
+	logger.Info("Request to join group.")
+
+	solved, err := getCaptcha().Verify(ctx.Request().Context(), req.CaptchaToken)
+	if err != nil {
+		logger.WithError(err).Error("Failed to verify CAPTCHA.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to join group.")
+		return
+	}
+	if !solved {
+		httperr.Write(ctx, http.StatusForbidden, "CAPTCHA not solved.")
+		return
+	}
+
+	// Only groups on the group finder can be joined from the website.
+	group, err := getCCBService().GetGroup(ctx.Request().Context(), groupID)
+	if err == ccb.ErrNotFound || (err == nil && !(group.Active && group.Listed && group.PublicListed)) {
+		httperr.Write(ctx, http.StatusNotFound, "Group not found.")
+		return
+	} else if err != nil {
+		logger.WithError(err).Error("Failed to get group.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to join group.")
+		return
+	}
+	if group.IsFull() {
+		httperr.Write(ctx, http.StatusConflict, "Group is full.")
+		return
+	}
+
+	details.CampusID = group.CampusID
+	individual, created, err := ccb.MatchOrCreateIndividual(ctx.Request().Context(), getCCBService(), details)
+	if err != nil {
+		logger.WithError(err).Error("Failed to match or create individual.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to join group.")
+		return
+	}
+	logger = logger.WithFields(logrus.Fields{
+		"individual_id":      individual.ID,
+		"individual_created": created,
+	})
+
+	if err := getCCBService().AddIndividualToGroup(ctx.Request().Context(), group.ID, individual.ID, ccb.GroupMembershipRequest); err != nil {
+		if created {
+			// The individual stays in CCB, so staff need their id to add them by hand.
+			logger.WithError(err).Error("Created individual but failed to add them to group.")
+		} else {
+			logger.WithError(err).Error("Failed to add individual to group.")
+		}
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to join group.")
+		return
+	}
+
+	// The request is in CCB for leaders to see, so the visitor does not wait for the
+	// notification and a failed one does not fail the request.
+	getJobs().Go("notify_group_leader", func(jobCtx context.Context) {
+		if err := notifyGroupLeader(jobCtx, group, details, message); err != nil {
+			logger.WithError(err).Error("Failed to notify group leader.")
+		}
+	})
+
+	logger.Info("Requested to join group.")
+	ctx.StatusCode(http.StatusAccepted)
+	writeJSON(ctx, map[string]string{"status": "requested"})
+}
+
+// parseIndividualDetails validates and normalizes the details people give about
+// themselves. If it returns false, an error has been written.
+func parseIndividualDetails(ctx iris.Context, firstName, lastName, email, phone string) (ccb.IndividualDetails, bool) {
+	details := ccb.IndividualDetails{
+		FirstName: strings.TrimSpace(firstName),
+		LastName:  strings.TrimSpace(lastName),
+	}
+	if details.FirstName == "" || details.LastName == "" {
+		httperr.Write(ctx, http.StatusBadRequest, "First and last name are required.")
+		return details, false
+	}
+	if email != "" {
+		normalized, ok := ccb.NormalizeEmail(email)
+		if !ok {
+			httperr.Write(ctx, http.StatusBadRequest, "Invalid email.")
+			return details, false
+		}
+		details.Email = normalized
+	}
+	if phone != "" {
+		normalized, ok := ccb.NormalizePhone(phone, getCCBConfig().DefaultCountryCode)
+		if !ok {
+			httperr.Write(ctx, http.StatusBadRequest, "Invalid phone.")
+			return details, false
+		}
+		details.Phone = normalized
+	}
+	if details.Email == "" && details.Phone == "" {
+		httperr.Write(ctx, http.StatusBadRequest, "Email or phone is required.")
+		return details, false
+	}
+	return details, true
+}
+
+// notifyGroupLeader tells the leader of the group about a request to join it.
+func notifyGroupLeader(ctx context.Context, group *ccb.Group, d ccb.IndividualDetails, message string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s has asked to join %s.\n\n", d.FirstName, d.LastName, group.Name)
+	if d.Email != "" {
+		fmt.Fprintf(&b, "Email: %s\n", d.Email)
+	}
+	if d.Phone != "" {
+		fmt.Fprintf(&b, "Phone: %s\n", d.Phone)
+	}
+	if message != "" {
+		fmt.Fprintf(&b, "\nMessage:\n%s\n", message)
+	}
+	b.WriteString("\nPlease approve or decline the request in CCB.\n")
+
+	msg := notify.Message{
+		Subject: "New request to join " + group.Name,
+		Text:    b.String(),
+	}
+	if group.Leader != nil && group.Leader.Email != "" {
+		msg.To = []string{group.Leader.Email}
+	}
+	return getNotifier().Notify(ctx, msg)
+}
+
+// groupParticipantAddRequest is the JSON body of a request to add a participant.
+type groupParticipantAddRequest struct {
+	IndividualID string                    `json:"individual_id"`
+	Status       ccb.GroupMembershipStatus `json:"status"` // add, invite or request. Defaults to add.
+}
+
+// groupParticipantAdd handles the POST route for adding an individual to a group.
+func groupParticipantAdd(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	groupID := ctx.Params().Get("id")
+
+	var req groupParticipantAddRequest
+	if err := ctx.ReadJSON(&req); err != nil || req.IndividualID == "" {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+	switch req.Status {
+	case "":
+		req.Status = ccb.GroupMembershipAdd
+	case ccb.GroupMembershipAdd, ccb.GroupMembershipInvite, ccb.GroupMembershipRequest:
+	default:
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid status, must be add, invite or request.")
+		return
+	}
+
+	if err := getCCBService().AddIndividualToGroup(ctx.Request().Context(), groupID, req.IndividualID, req.Status); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"group_id":      groupID,
+			"individual_id": req.IndividualID,
+		}).Error("Failed to add individual to group.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to add individual to group.")
+		return
+	}
+
+	ctx.StatusCode(http.StatusNoContent)
+}
+
+// groupParticipantRemove handles the DELETE route for removing an individual from
+// a group.
+func groupParticipantRemove(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	groupID := ctx.Params().Get("id")
+	individualID := ctx.Params().Get("individual_id")
+
+	if err := getCCBService().RemoveIndividualFromGroup(ctx.Request().Context(), groupID, individualID); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"group_id":      groupID,
+			"individual_id": individualID,
+		}).Error("Failed to remove individual from group.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to remove individual from group.")
+		return
+	}
+
+	ctx.StatusCode(http.StatusNoContent)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	iris "github.com/kataras/iris/v12"
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// groupFilter filters groups on the query parameters "campus", "type", "day" and
+// "has_space". Names are compared case insensitively and empty fields are ignored.
+type groupFilter struct {
+	Campus   string
+	Type     string
+	Day      string
+	HasSpace bool
+}
+
+func parseGroupFilter(ctx iris.Context) groupFilter {
+	return groupFilter{
+		Campus:   strings.TrimSpace(ctx.URLParam("campus")),
+		Type:     strings.TrimSpace(ctx.URLParam("type")),
+		Day:      strings.TrimSpace(ctx.URLParam("day")),
+		HasSpace: ctx.URLParam("has_space") == "true",
+	}
+}
+
+// match returns true if the group matches every criteria of the filter.
+func (f groupFilter) match(g ccb.Group) bool {
+	if f.Campus != "" && !strings.EqualFold(g.Campus, f.Campus) {
+		return false
+	}
+	if f.Type != "" && !strings.EqualFold(g.Type, f.Type) {
+		return false
+	}
+	if f.Day != "" && (g.Schedule == nil || !strings.EqualFold(g.Schedule.Day, f.Day)) {
+		return false
+	}
+	if f.HasSpace && g.IsFull() {
+		return false
+	}
+	return true
+}
+
+// groupsGet handles the GET route for groups.
+// it optionally takes a parameter of "modified_since" as a date, and the filter
+// parameters of groupFilter.
+// returns every matching group in JSON format
+func groupsGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+
+	modifiedSinceStr := ctx.URLParam("modified_since")
+	filter := parseGroupFilter(ctx)
+
+	logger.WithFields(logrus.Fields{
+		"modified_since": modifiedSinceStr,
+		"filter":         filter,
+	}).Info("Get groups.")
+
+	modifiedSince, err := parseDateParam(modifiedSinceStr)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid modified since date.")
+		return
+	}
+
+	groups := []ccb.Group{}
+	if err := ccb.EachGroup(ctx.Request().Context(), getCCBService(), ccb.ListGroupsRequest{
+		ModifiedSince: modifiedSince,
+	}, func(g ccb.Group) error {
+		if filter.match(g) {
+			groups = append(groups, g)
+		}
+		return nil
+	}); err != nil {
+		logger.WithError(err).Error("Failed to list groups.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to list groups.")
+		return
+	}
+
+	writeJSON(ctx, map[string]interface{}{"groups": groups})
+}
+
+// groupGet handles the GET route for a single group.
+func groupGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	id := ctx.Params().Get("id")
+
+	group, err := getCCBService().GetGroup(ctx.Request().Context(), id)
+	if err == ccb.ErrNotFound {
+		httperr.Write(ctx, http.StatusNotFound, "Group not found.")
+		return
+	} else if err != nil {
+		logger.WithError(err).WithField("group_id", id).Error("Failed to get group.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to get group.")
+		return
+	}
+
+	writeJSON(ctx, group)
+}
+
+// groupParticipantsGet handles the GET route for the participants of a group.
+func groupParticipantsGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	id := ctx.Params().Get("id")
+
+	participants, err := getCCBService().ListGroupParticipants(ctx.Request().Context(), id)
+	if err == ccb.ErrNotFound {
+		httperr.Write(ctx, http.StatusNotFound, "Group not found.")
+		return
+	} else if err != nil {
+		logger.WithError(err).WithField("group_id", id).Error("Failed to list group participants.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to list group participants.")
+		return
+	}
+
+	writeJSON(ctx, map[string]interface{}{"participants": participants})
+}
+
+// publicGroup is a group as shown on the website's group finder. It leaves out the
+// contact details of the leader and the street address, as groups often meet in
+// homes.
+type publicGroup struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Area        string             `json:"area,omitempty"`
+	Campus      string             `json:"campus,omitempty"`
+	LeaderName  string             `json:"leader_name,omitempty"`
+	Schedule    *ccb.GroupSchedule `json:"schedule,omitempty"`
+	City        string             `json:"city,omitempty"`
+	State       string             `json:"state,omitempty"`
+	Childcare   bool               `json:"childcare"`
+	Full        bool               `json:"full"`
+}
+
+func newPublicGroup(g ccb.Group) publicGroup {
+	p := publicGroup{
+		ID:          g.ID,
+		Name:        g.Name,
+		Description: g.Description,
+		Type:        g.Type,
+		Area:        g.Area,
+		Campus:      g.Campus,
+		Schedule:    g.Schedule,
+		Childcare:   g.Childcare,
+		Full:        g.IsFull(),
+	}
+	if g.Leader != nil {
+		p.LeaderName = g.Leader.Name
+	}
+	if g.Location != nil {
+		p.City, p.State = g.Location.City, g.Location.State
+	}
+	return p
+}
+
+// publicGroupsCacheKey is the key the public groups are cached under.
+const publicGroupsCacheKey = "groups"
+
+// publicGroupsGet handles the public GET route for the group finder.
+// it optionally takes the filter parameters of groupFilter.
+// returns the active groups listed publicly in CCB in JSON format
+func publicGroupsGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	filter := parseGroupFilter(ctx)
+
+	// The website fetches every group, so all listed groups are cached and filtered
+	// for each request.
+	var listed []ccb.Group
+	if cached, ok := getPublicGroupsCache().Get(publicGroupsCacheKey); ok {
+		listed = cached.([]ccb.Group)
+	} else {
+		if err := ccb.EachGroup(ctx.Request().Context(), getCCBService(), ccb.ListGroupsRequest{}, func(g ccb.Group) error {
+			if g.Active && g.Listed && g.PublicListed {
+				listed = append(listed, g)
+			}
+			return nil
+		}); err != nil {
+			logger.WithError(err).Error("Failed to list groups.")
+			httperr.Write(ctx, http.StatusInternalServerError, "Failed to list groups.")
+			return
+		}
+		getPublicGroupsCache().Set(publicGroupsCacheKey, listed)
+	}
+
+	groups := []publicGroup{}
+	for _, g := range listed {
+		if filter.match(g) {
+			groups = append(groups, newPublicGroup(g))
+		}
+	}
+
+	writeJSON(ctx, map[string]interface{}{"groups": groups})
+}
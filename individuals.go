@@ -0,0 +1,138 @@
+package main
+
+import (
+	"net/http"
+
+	iris "github.com/kataras/iris/v12"
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+)
+
+// individualProfile is the profile of an individual, with the details requested
+// with expand.
+type individualProfile struct {
+	*ccb.Individual
+	Groups  []ccb.IndividualGroup `json:"groups,omitempty"`
+	InGroup *bool                 `json:"in_group,omitempty"` // Only set when groups are expanded. Pending groups do not count.
+}
+
+// individualGet handles the GET route for the profile of an individual.
+// it optionally takes "expand=groups" to include the groups they are in, with
+// their role and join date.
+func individualGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	id := ctx.Params().Get("id")
+
+	individual, err := getCCBService().GetIndividual(ctx.Request().Context(), id)
+	if err == ccb.ErrNotFound {
+		httperr.Write(ctx, http.StatusNotFound, "Individual not found.")
+		return
+	} else if err != nil {
+		logger.WithError(err).WithField("individual_id", id).Error("Failed to get individual.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to get individual.")
+		return
+	}
+	profile := individualProfile{Individual: individual}
+
+	if hasExpand(ctx, "groups") {
+		groups, err := getCCBService().GetIndividualGroups(ctx.Request().Context(), id)
+		if err != nil && err != ccb.ErrNotFound {
+			logger.WithError(err).WithField("individual_id", id).Error("Failed to get individual groups.")
+			httperr.Write(ctx, http.StatusInternalServerError, "Failed to get individual groups.")
+			return
+		}
+		inGroup := false
+		for _, g := range groups {
+			if !g.Pending {
+				inGroup = true
+				break
+			}
+		}
+		profile.Groups = groups
+		profile.InGroup = &inGroup
+	}
+
+	writeJSON(ctx, profile)
+}
+
+// significantEventTypesGet handles the GET route for the types of significant
+// events.
+func significantEventTypesGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+
+	types, err := getCCBService().ListSignificantEventTypes(ctx.Request().Context())
+	if err != nil {
+		logger.WithError(err).Error("Failed to list significant event types.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to list significant event types.")
+		return
+	}
+
+	writeJSON(ctx, map[string]interface{}{"types": types})
+}
+
+// individualSignificantEventsGet handles the GET route for the significant events
+// of an individual, such as their baptism and Growth Track steps.
+func individualSignificantEventsGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	id := ctx.Params().Get("id")
+
+	events, err := getCCBService().GetIndividualSignificantEvents(ctx.Request().Context(), id)
+	if err == ccb.ErrNotFound {
+		httperr.Write(ctx, http.StatusNotFound, "Individual not found.")
+		return
+	} else if err != nil {
+		logger.WithError(err).WithField("individual_id", id).Error("Failed to get significant events.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to get significant events.")
+		return
+	}
+
+	writeJSON(ctx, map[string]interface{}{"significant_events": events})
+}
+
+// familyResponse is a family with the ids of the members to contact about it.
+type familyResponse struct {
+	*ccb.Family
+	PrimaryContactID string   `json:"primary_contact_id,omitempty"`
+	ParentIDs        []string `json:"parent_ids"` // Primary contact and spouse.
+}
+
+// familyGet handles the GET route for a family, so follow-up about a child can
+// reach their parents.
+func familyGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	id := ctx.Params().Get("id")
+
+	family, err := getCCBService().GetFamily(ctx.Request().Context(), id)
+	if err == ccb.ErrNotFound {
+		httperr.Write(ctx, http.StatusNotFound, "Family not found.")
+		return
+	} else if err != nil {
+		logger.WithError(err).WithField("family_id", id).Error("Failed to get family.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to get family.")
+		return
+	}
+
+	resp := familyResponse{Family: family, ParentIDs: []string{}}
+	if contact := family.PrimaryContact(); contact != nil {
+		resp.PrimaryContactID = contact.ID
+	}
+	for _, parent := range family.Parents() {
+		resp.ParentIDs = append(resp.ParentIDs, parent.ID)
+	}
+	writeJSON(ctx, resp)
+}
+
+// customFieldLabelsGet handles the GET route for the labels of user-defined fields.
+func customFieldLabelsGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+
+	labels, err := getCCBService().GetCustomFieldLabels(ctx.Request().Context())
+	if err != nil {
+		logger.WithError(err).Error("Failed to get custom field labels.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to get custom field labels.")
+		return
+	}
+
+	writeJSON(ctx, map[string]interface{}{"custom_fields": labels})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// backgroundJobs tracks work that runs outside of a request, such as notifications
+// and scheduled reports, so it can be drained before the server exits.
+type backgroundJobs struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var (
+	jobsOnce sync.Once
+	jobs     *backgroundJobs
+)
+
+// getJobs returns the background jobs shared by all handlers, which are drained
+// when the server shuts down.
+func getJobs() *backgroundJobs {
+	jobsOnce.Do(func() {
+		jobs = newBackgroundJobs()
+	})
+	return jobs
+}
+
+func newBackgroundJobs() *backgroundJobs {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &backgroundJobs{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Go runs fn in a new goroutine. The context passed to fn carries a logger for the
+// job and is cancelled if the job is still running when the shutdown deadline passes.
+func (j *backgroundJobs) Go(name string, fn func(ctx context.Context)) {
+	logger := logrus.NewEntry(logrus.StandardLogger()).WithField("job", name)
+	ctx := vouslog.WithLogger(j.ctx, logger)
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		fn(ctx)
+	}()
+}
+
+// Wait blocks until all jobs have finished or ctx is done. If ctx is done first the
+// remaining jobs are cancelled and the context error is returned.
+func (j *backgroundJobs) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		j.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		j.cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,65 @@
+// Package cache provides a small in-memory cache with expiring entries, for
+// values which are expensive to fetch from CCB and may be slightly stale.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache is an in-memory cache of values which expire after the TTL. It is safe for
+// concurrent use.
+type Cache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// New creates a cache with entries which expire after ttl. A ttl of zero or less
+// disables caching.
+func New(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		entries: map[string]entry{},
+	}
+}
+
+// Get returns the value cached for the key, if it has not expired.
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set caches the value for the key. Expired entries are removed as new ones are
+// added, so the cache does not grow with keys which are never read again.
+func (c *Cache) Set(key string, value interface{}) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = entry{value: value, expires: now.Add(c.ttl)}
+}
@@ -0,0 +1,78 @@
+// Package captcha verifies CAPTCHA tokens from the website with the siteverify API
+// shared by Cloudflare Turnstile, hCaptcha and reCAPTCHA.
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds configuration for verifying CAPTCHA tokens.
+type Config struct {
+	Secret    string        `envconfig:"CAPTCHA_SECRET"` // Secret key of the site. Verification is disabled if empty.
+	VerifyURL string        `envconfig:"CAPTCHA_VERIFY_URL" default:"https://challenges.cloudflare.com/turnstile/v0/siteverify"`
+	Timeout   time.Duration `envconfig:"CAPTCHA_TIMEOUT"    default:"5s"`
+}
+
+// Verifier checks tokens solved by visitors to the website.
+type Verifier struct {
+	verifyURL string
+	secret    string
+	client    *http.Client
+}
+
+// New creates a new Verifier from the config. It returns nil if no secret is
+// configured.
+func New(cfg Config) (*Verifier, error) {
+	if cfg.Secret == "" {
+		return nil, nil
+	}
+	if _, err := url.ParseRequestURI(cfg.VerifyURL); err != nil {
+		return nil, errors.New("parse verify url: " + err.Error())
+	}
+	return &Verifier{
+		verifyURL: cfg.VerifyURL,
+		secret:    cfg.Secret,
+		client:    &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// Verify returns whether the token is a solved CAPTCHA. Tokens can only be
+// verified once. An error is only returned if the token could not be checked.
+func (v *Verifier) Verify(ctx context.Context, token string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+
+	form := url.Values{}
+	form.Set("secret", v.secret)
+	form.Set("response", token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, errors.New("create request: " + err.Error())
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, errors.New("do request: " + err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, errors.New("unexpected response from siteverify: " + strconv.Itoa(resp.StatusCode))
+	}
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, errors.New("decode response: " + err.Error())
+	}
+	return result.Success, nil
+}
@@ -0,0 +1,40 @@
+package ccb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Answer is the answer to a single question on a form. Multiple choice questions
+// can have several values, and unanswered questions have none.
+type Answer struct {
+	Question string   `json:"question"`
+	Values   []string `json:"values"`
+}
+
+// answersMap returns the answers keyed by question, with multiple values joined by
+// a comma. If a question appears more than once only the first is kept and a
+// warning is returned.
+func answersMap(answers []Answer) (map[string]string, []string) {
+	m := make(map[string]string, len(answers))
+	var warnings []string
+	for _, a := range answers {
+		if _, ok := m[a.Question]; ok {
+			warnings = append(warnings, fmt.Sprintf("duplicate question %q", a.Question))
+			continue
+		}
+		m[a.Question] = strings.Join(a.Values, ", ")
+	}
+	return m, warnings
+}
+
+// Values returns the values answering the question, matched case insensitively.
+// Returns nil if the question was not asked.
+func (r FormResponse) Values(question string) []string {
+	for _, a := range r.AnswerList {
+		if strings.EqualFold(a.Question, question) {
+			return a.Values
+		}
+	}
+	return nil
+}
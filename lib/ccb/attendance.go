@@ -0,0 +1,164 @@
+package ccb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb/ccbxml"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// occurrenceLayout is the layout of event occurrences in CCB.
+const occurrenceLayout = "2006-01-02 15:04:05"
+
+// Attendance is the attendance of an occurrence of an event.
+type Attendance struct {
+	EventID    string     `json:"event_id"`
+	EventName  string     `json:"event_name,omitempty"`
+	Occurrence time.Time  `json:"occurrence"`
+	DidNotMeet bool       `json:"did_not_meet"`
+	HeadCount  *int       `json:"head_count,omitempty"` // Nil if not recorded.
+	Attendees  []Attendee `json:"attendees"`
+	Topic      string     `json:"topic,omitempty"`
+	Notes      string     `json:"notes,omitempty"`
+}
+
+// Attendee is an individual who attended an event.
+type Attendee struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// ListAttendanceRequest is a request for attendance between the dates From and To,
+// both inclusive.
+type ListAttendanceRequest struct {
+	From    time.Time
+	To      time.Time
+	EventID string // Only attendance of this event, if set.
+}
+
+// RecordAttendanceRequest is a request to record the attendance of an occurrence
+// of an event.
+type RecordAttendanceRequest struct {
+	EventID       string
+	Occurrence    time.Time
+	DidNotMeet    bool
+	HeadCount     *int     // Not recorded if nil.
+	IndividualIDs []string // Individuals who attended.
+	Topic         string
+	Notes         string
+}
+
+// ListAttendance returns the attendance of events which occurred in the date range
+// of the request.
+func (svc *defaultService) ListAttendance(ctx context.Context, req ListAttendanceRequest) ([]Attendance, error) {
+	logger := vouslog.GetLogger(ctx)
+	loc := svc.config.Location()
+	logger.WithFields(logrus.Fields{
+		"from":     req.From.In(loc).Format(dateLayout),
+		"to":       req.To.In(loc).Format(dateLayout),
+		"event_id": req.EventID,
+	}).Info("Listing attendance from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "attendance_profiles")
+	q.Add("start_date", req.From.In(loc).Format(dateLayout))
+	q.Add("end_date", req.To.In(loc).Format(dateLayout))
+
+	attendance := []Attendance{}
+	var warnings []string
+	if err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		return ccbxml.EachEvent(r, func(v *ccbxml.Event) error {
+			// attendance_profiles has no parameter for the event, so filter here.
+			if req.EventID != "" && v.ID != req.EventID {
+				return nil
+			}
+			a, err := attendanceFromCCB(v, loc)
+			if err != nil {
+				warnings = append(warnings, err.Error())
+				return nil
+			}
+			attendance = append(attendance, *a)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	if len(warnings) > 0 {
+		logger.WithField("warnings", warnings).Warn("Malformed attendance from CCB.")
+	}
+	return attendance, nil
+}
+
+// RecordAttendance records the attendance of an occurrence of an event, replacing
+// any attendance already recorded for it.
+func (svc *defaultService) RecordAttendance(ctx context.Context, req RecordAttendanceRequest) error {
+	if req.EventID == "" || req.Occurrence.IsZero() {
+		return errors.New("event id and occurrence are required")
+	}
+	vouslog.GetLogger(ctx).WithFields(logrus.Fields{
+		"event_id":     req.EventID,
+		"occurrence":   req.Occurrence,
+		"did_not_meet": req.DidNotMeet,
+		"attendees":    len(req.IndividualIDs),
+	}).Info("Recording attendance in CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "create_event_attendance")
+	q.Add("event_id", req.EventID)
+	q.Add("occurrence", req.Occurrence.In(svc.config.Location()).Format(occurrenceLayout))
+	q.Add("did_not_meet", strconv.FormatBool(req.DidNotMeet))
+	if req.HeadCount != nil {
+		q.Add("head_count", strconv.Itoa(*req.HeadCount))
+	}
+	if len(req.IndividualIDs) > 0 {
+		q.Add("attendees", strings.Join(req.IndividualIDs, ","))
+	}
+	if req.Topic != "" {
+		q.Add("topic", req.Topic)
+	}
+	if req.Notes != "" {
+		q.Add("notes", req.Notes)
+	}
+
+	_, err := svc.postCCB(ctx, q)
+	return err
+}
+
+// attendanceFromCCB converts an event of an attendance_profiles response.
+func attendanceFromCCB(v *ccbxml.Event, loc *time.Location) (*Attendance, error) {
+	occurrence, err := time.ParseInLocation(occurrenceLayout, v.Occurrence, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid occurrence %q of event %s", v.Occurrence, v.ID)
+	}
+
+	a := &Attendance{
+		EventID:    v.ID,
+		EventName:  strings.TrimSpace(v.Name),
+		Occurrence: occurrence,
+		DidNotMeet: v.DidNotMeet == "true",
+		Attendees:  []Attendee{},
+		Topic:      strings.TrimSpace(v.Topic),
+		Notes:      strings.TrimSpace(v.Notes),
+	}
+	if s := strings.TrimSpace(v.HeadCount); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid head count %q of event %s", v.HeadCount, v.ID)
+		}
+		a.HeadCount = &n
+	}
+	for _, x := range v.Attendees {
+		if x != nil && x.ID != "" {
+			a.Attendees = append(a.Attendees, Attendee{ID: x.ID, Name: strings.TrimSpace(x.Name)})
+		}
+	}
+	return a, nil
+}
@@ -0,0 +1,60 @@
+package ccb
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb/ccbxml"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+)
+
+// Campus is a campus of the church.
+type Campus struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Slug   string `json:"slug"` // Used to refer to the campus in the API, e.g. jdd.
+	Active bool   `json:"active"`
+}
+
+// ListCampuses returns every campus in CCB.
+func (svc *defaultService) ListCampuses(ctx context.Context) ([]Campus, error) {
+	vouslog.GetLogger(ctx).Info("Listing campuses from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "campus_list")
+
+	campuses := []Campus{}
+	if err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		return ccbxml.EachCampus(r, func(c *ccbxml.Campus) error {
+			campuses = append(campuses, Campus{
+				ID:     c.ID,
+				Name:   strings.TrimSpace(c.Name),
+				Slug:   svc.campusSlug(c.ID, c.Name),
+				Active: c.Active == "true",
+			})
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return campuses, nil
+}
+
+// campusSlug returns the configured slug of the campus, or its slugified name.
+func (svc *defaultService) campusSlug(id, name string) string {
+	if slug, ok := svc.config.CampusSlugs[id]; ok {
+		return slug
+	}
+	return Slugify(name)
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify returns the name lower-cased with runs of other characters replaced by
+// an underscore, e.g. "Johnson Drive" becomes johnson_drive.
+func Slugify(name string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "_"), "_")
+}
@@ -5,14 +5,18 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff"
+	"github.com/mruVOUS/ccb-webflow-api/lib/cache"
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb/ccbxml"
 	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
 	"github.com/sirupsen/logrus"
 )
@@ -21,37 +25,132 @@ import (
 type Config struct {
 	Username       string        `envconfig:"CCB_USERNAME"`
 	Password       string        `envconfig:"CCB_PASSWORD"`
-	APIURL         string        `envconfig:"CCB_API_URL"`                           // API URL for the CCB API.
-	DefaultTimeout time.Duration `envconfig:"CCB_DEFAULT_TIMEOUT"      default:"5s"` // Timeout for HTTP calls to CCB.
+	APIURL         string        `envconfig:"CCB_API_URL"`                             // API URL for the CCB API.
+	DefaultTimeout time.Duration `envconfig:"CCB_DEFAULT_TIMEOUT"      default:"5s"`   // Timeout for HTTP calls to CCB.
+	Timezone       Location      `envconfig:"CCB_TIMEZONE" default:"America/New_York"` // Time zone of the church, which CCB timestamps are in.
+
+	CustomFieldLabelsTTL time.Duration `envconfig:"CCB_CUSTOM_FIELD_LABELS_TTL" default:"1h"` // How long the labels of user-defined fields are cached.
+
+	ContactFields // Profile fields used to build the contact details of form responses.
+	FormsConfig   // Forms and campuses known to the API.
+	QueuesConfig  // Process queues known to the API.
 }
 
-// FormID represents an enum for a form response in CCB.
+// FormID is the id of a form in CCB. The forms are configured in FormsConfig.
 type FormID int
 
-// FormID represents enums for types of form responses to retrieve from CCB.
-const (
-	FormIDConnectCardITech       FormID = 0 // TODO: Set this.
-	FormIDConnectCardJDD         FormID = 85
-	FormIDGrowthTrackSignUpITech FormID = 2 // TODO: Set this.
-	FormIDGrowthTrackSignUpJDD   FormID = 3 // TODO: Set this.
-)
-
 // Service defines functions for communicating with the CCB service.
 type Service interface {
 	// GetFormResponses returns form responses for the supplied form ID.
 	GetFormResponses(context.Context, GetFormResponsesRequest) (*GetFormResponsesResponse, error)
+
+	// StreamFormResponses calls fn with each form response of the page as it is read
+	// from CCB and returns how many were read. Errors from fn are returned as is.
+	StreamFormResponses(ctx context.Context, req GetFormResponsesRequest, fn func(FormResponse) error) (int, error)
+
+	// GetIndividual returns the individual with the id, or ErrNotFound.
+	GetIndividual(ctx context.Context, id string) (*Individual, error)
+
+	// ListCampuses returns every campus.
+	ListCampuses(context.Context) ([]Campus, error)
+
+	// ListGroups returns a page of groups, without their participants.
+	ListGroups(context.Context, ListGroupsRequest) (*ListGroupsResponse, error)
+
+	// StreamGroups calls fn with each group of the page as it is read from CCB, like
+	// StreamFormResponses.
+	StreamGroups(ctx context.Context, req ListGroupsRequest, fn func(Group) error) (int, error)
+
+	// GetGroup returns the group with the id, or ErrNotFound.
+	GetGroup(ctx context.Context, id string) (*Group, error)
+
+	// ListGroupParticipants returns the participants of the group, or ErrNotFound.
+	ListGroupParticipants(ctx context.Context, groupID string) ([]GroupParticipant, error)
+
+	// AddIndividualToGroup adds the individual to the group with the status.
+	AddIndividualToGroup(ctx context.Context, groupID, individualID string, status GroupMembershipStatus) error
+
+	// RemoveIndividualFromGroup removes the individual from the group.
+	RemoveIndividualFromGroup(ctx context.Context, groupID, individualID string) error
+
+	// SearchIndividuals returns the individuals matching every field of the request.
+	SearchIndividuals(context.Context, SearchIndividualsRequest) ([]Individual, error)
+
+	// CreateIndividual creates an individual and returns it.
+	CreateIndividual(context.Context, CreateIndividualRequest) (*Individual, error)
+
+	// ListPublicEvents returns the occurrences of events on the public calendar
+	// between the dates from and to, both inclusive.
+	ListPublicEvents(ctx context.Context, from, to time.Time) ([]EventOccurrence, error)
+
+	// GetEvent returns the event with the id, or ErrNotFound.
+	GetEvent(ctx context.Context, id string) (*Event, error)
+
+	// ListAttendance returns the attendance of events which occurred in the date
+	// range of the request.
+	ListAttendance(context.Context, ListAttendanceRequest) ([]Attendance, error)
+
+	// RecordAttendance records the attendance of an occurrence of an event.
+	RecordAttendance(context.Context, RecordAttendanceRequest) error
+
+	// ListProcesses returns every process.
+	ListProcesses(context.Context) ([]Process, error)
+
+	// ListQueues returns the queues of the process, or ErrNotFound.
+	ListQueues(ctx context.Context, processID string) ([]Queue, error)
+
+	// ListQueueIndividuals returns the individuals in the queue, or ErrNotFound.
+	ListQueueIndividuals(ctx context.Context, queueID string) ([]QueueIndividual, error)
+
+	// AddIndividualToQueue adds the individual to the queue.
+	AddIndividualToQueue(context.Context, AddIndividualToQueueRequest) error
+
+	// ManageQueueIndividual updates the individual in the queue.
+	ManageQueueIndividual(context.Context, ManageQueueIndividualRequest) error
+
+	// ListSignificantEventTypes returns every type of significant event.
+	ListSignificantEventTypes(context.Context) ([]SignificantEventType, error)
+
+	// GetIndividualSignificantEvents returns the significant events of the
+	// individual, oldest first, or ErrNotFound.
+	GetIndividualSignificantEvents(ctx context.Context, individualID string) ([]SignificantEvent, error)
+
+	// GetIndividualGroups returns the groups the individual is in with their role,
+	// the ones they lead first, or ErrNotFound.
+	GetIndividualGroups(ctx context.Context, individualID string) ([]IndividualGroup, error)
+
+	// GetFamily returns the family with the id, with the adults first, or
+	// ErrNotFound.
+	GetFamily(ctx context.Context, id string) (*Family, error)
+
+	// GetCustomFieldLabels returns the labels of the user-defined fields.
+	GetCustomFieldLabels(context.Context) ([]CustomFieldLabel, error)
+
+	// ListBatches returns the giving batches posted between the dates from and to,
+	// both inclusive, without who gave.
+	ListBatches(ctx context.Context, from, to time.Time) ([]Batch, error)
+
+	// ListTransactionDetailTypes returns every fund.
+	ListTransactionDetailTypes(context.Context) ([]TransactionDetailType, error)
 }
 
+// ErrNotFound is returned when the requested record does not exist in CCB.
+var ErrNotFound = errors.New("not found in CCB")
+
 type defaultService struct {
 	config Config
+	forms  Forms
 	client *http.Client
+	labels *cache.Cache // Labels of user-defined fields.
 }
 
 // New creates a new CCB Service to talk to the Church Community Build (CCB) service.
 func New(cfg Config) Service {
 	return &defaultService{
 		config: cfg,
+		forms:  cfg.Forms(),
 		client: &http.Client{},
+		labels: cache.New(cfg.CustomFieldLabelsTTL),
 	}
 }
 
@@ -63,7 +162,7 @@ const (
 // GetFormResponsesRequest represents a request to GetFormResponses.
 type GetFormResponsesRequest struct {
 	FormID        FormID
-	ModifiedSince *time.Time // Day
+	ModifiedSince *time.Time // Only the day is sent to CCB. Use FormResponseFilter for more precision.
 	Page          int
 	PageSize      int
 }
@@ -75,15 +174,47 @@ type GetFormResponsesResponse struct {
 
 // FormResponse represents the responses to forms such as Connect Cards.
 type FormResponse struct {
-	ID          string            `json:"id,omitempty"`
+	ID          string            `json:"id,omitempty"` // ID of the response.
+	FormID      string            `json:"form_id,omitempty"`
+	Campus      string            `json:"campus,omitempty"`     // Slug of the campus of the form.
+	Individual  *IndividualRef    `json:"individual,omitempty"` // The individual who filled in the form, if known.
 	ProfileInfo map[string]string `json:"profile_info,omitempty"`
-	Answers     map[string]string `json:"answers,omitempty"`
-	Created     string            `json:"created,omitempty"`
-	Modified    string            `json:"modified,omitempty"`
+	Contact     *Contact          `json:"contact,omitempty"` // Normalized from ProfileInfo.
+	Answers     map[string]string `json:"answers,omitempty"` // Deprecated: Use AnswerList, which keeps order and multiple values.
+	AnswerList  []Answer          `json:"answer_list"`
+	Payment     *Payment          `json:"payment,omitempty"`  // Set for forms which take payments.
+	Created     *time.Time        `json:"created,omitempty"`  // In the church time zone.
+	Modified    *time.Time        `json:"modified,omitempty"` // In the church time zone.
+	Warnings    []string          `json:"warnings,omitempty"` // Problems found parsing the response from CCB.
+}
+
+// IndividualRef links a record to an individual in CCB.
+type IndividualRef struct {
+	ID      string      `json:"id"`
+	Name    string      `json:"name,omitempty"`
+	Profile *Individual `json:"profile,omitempty"` // Only set when expanded.
 }
 
+// timestampLayout is the layout CCB uses for created and modified timestamps.
+const timestampLayout = "2006-01-02 15:04:05"
+
 // GetFormResponses returns form responses for the supplied form ID.
 func (svc *defaultService) GetFormResponses(ctx context.Context, req GetFormResponsesRequest) (*GetFormResponsesResponse, error) {
+	var responses []FormResponse
+	if _, err := svc.StreamFormResponses(ctx, req, func(f FormResponse) error {
+		responses = append(responses, f)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &GetFormResponsesResponse{
+		Responses: responses,
+	}, nil
+}
+
+// StreamFormResponses calls fn with each form response of the page as it is read
+// from CCB, so pages are not held in memory.
+func (svc *defaultService) StreamFormResponses(ctx context.Context, req GetFormResponsesRequest, fn func(FormResponse) error) (int, error) {
 	logger := vouslog.GetLogger(ctx)
 	logger.WithFields(logrus.Fields{
 		"form_id":   req.FormID,
@@ -98,12 +229,105 @@ func (svc *defaultService) GetFormResponses(ctx context.Context, req GetFormResp
 	q.Add("per_page", strconv.Itoa(req.PageSize))
 	q.Add("form_id", strconv.Itoa(int(req.FormID)))
 	if req.ModifiedSince != nil {
-		// Only supports year-month-date.
-		q.Add("modified_since", req.ModifiedSince.Format("2006-01-02"))
+		// Only supports year-month-date, in the church time zone.
+		q.Add("modified_since", req.ModifiedSince.In(svc.config.Location()).Format(dateLayout))
+	}
+
+	// Pages can be large, so each response is built and handled as it's read.
+	var n int
+	form, hasForm := svc.forms.ByID(req.FormID)
+	err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		return ccbxml.EachFormResponse(r, func(v *ccbxml.FormResponse) error {
+			n++
+			f := formResponseFromCCB(ctx, v, svc.config.ContactFields, svc.config.Location())
+			if hasForm {
+				f.Campus = form.Campus
+			}
+			return recordErr(fn(f))
+		})
+	})
+	return n, err
+}
+
+// callCCB calls the CCB API with the query parameters, which must include the srv,
+// and decodes the XML response. Errors returned in the CCB payload are returned as
+// an error.
+func (svc *defaultService) callCCB(ctx context.Context, q url.Values) (*ccbxml.Response, error) {
+	return svc.doCCB(ctx, http.MethodGet, q)
+}
+
+// postCCB is like callCCB for services which change data in CCB, which must be
+// called with a POST.
+func (svc *defaultService) postCCB(ctx context.Context, q url.Values) (*ccbxml.Response, error) {
+	return svc.doCCB(ctx, http.MethodPost, q)
+}
+
+func (svc *defaultService) doCCB(ctx context.Context, method string, q url.Values) (*ccbxml.Response, error) {
+	body, err := svc.openCCB(ctx, method, q)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var data ccbxml.Response
+	if err := xml.NewDecoder(body).Decode(&data); err != nil {
+		ccbDecodeFailuresTotal.Inc(q.Get("srv"))
+		return nil, errors.New("unmarshal xml body: " + err.Error())
+	}
+	if err := responseErrors(ctx, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// streamCCB is like callCCB for list services, but decodes the response with
+// decode, such as ccbxml.EachGroup, so records are handled one at a time as they
+// are read rather than holding the whole page in memory. Errors from handling a
+// record, wrapped with recordErr, are returned as is.
+func (svc *defaultService) streamCCB(ctx context.Context, q url.Values, decode func(io.Reader) (*ccbxml.Response, error)) error {
+	body, err := svc.openCCB(ctx, http.MethodGet, q)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	data, err := decode(body)
+	if rerr, ok := err.(recordError); ok {
+		return rerr.err
+	}
+	if err != nil {
+		ccbDecodeFailuresTotal.Inc(q.Get("srv"))
+		return errors.New("unmarshal xml body: " + err.Error())
+	}
+	return responseErrors(ctx, data)
+}
+
+// recordError is an error from handling a streamed record rather than from
+// decoding the response.
+type recordError struct {
+	err error
+}
+
+func (e recordError) Error() string {
+	return e.err.Error()
+}
+
+// recordErr wraps a non-nil error from handling a streamed record, so streamCCB
+// returns it as is.
+func recordErr(err error) error {
+	if err == nil {
+		return nil
 	}
+	return recordError{err}
+}
+
+// openCCB calls the CCB API with the query parameters and returns the body of a
+// successful response, which must be closed.
+func (svc *defaultService) openCCB(ctx context.Context, method string, q url.Values) (io.ReadCloser, error) {
+	logger := vouslog.GetLogger(ctx)
 
 	// Build the do the HTTP request.
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, svc.config.APIURL+"/api.php?"+q.Encode(), nil)
+	httpReq, err := http.NewRequestWithContext(ctx, method, svc.config.APIURL+"/api.php?"+q.Encode(), nil)
 	if err != nil {
 		return nil, errors.New("create request: " + err.Error())
 	}
@@ -113,79 +337,114 @@ func (svc *defaultService) GetFormResponses(ctx context.Context, req GetFormResp
 	if err != nil {
 		return nil, errors.New("do request with retry: " + err.Error())
 	}
-	if httpResp.Body != nil {
-		defer httpResp.Body.Close()
-	}
 
 	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
 		// Log the response here for debugging.
-		msg, _ := ioutil.ReadAll(httpResp.Body) // Best effort.
+		msg, _ := ioutil.ReadAll(io.LimitReader(httpResp.Body, maxErrorBody)) // Best effort.
 		logger.WithFields(logrus.Fields{
 			"ccb_status_code": httpResp.StatusCode,
 			"ccb_response":    msg,
 		}).Error("Unexpected response from CCB.")
 		return nil, errors.New("unexpected response from CCB: " + strconv.Itoa(httpResp.StatusCode))
 	}
-
-	formResponses, err := svc.handleFormsCCBResponse(ctx, httpResp)
-	if err != nil {
-		return nil, errors.New("handle forms in CCB response: " + err.Error())
-	}
-
-	return &GetFormResponsesResponse{
-		Responses: formResponses,
-	}, nil
+	return httpResp.Body, nil
 }
 
-func (svc *defaultService) handleFormsCCBResponse(ctx context.Context, resp *http.Response) ([]FormResponse, error) {
-	logger := vouslog.GetLogger(ctx)
+// maxErrorBody is the most of an unexpected response from CCB which is logged.
+const maxErrorBody = 64 << 10
 
-	var data ccbResponse
-	if err := xml.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, errors.New("unmarshal xml body: " + err.Error())
-	}
-
-	// Handle any errors in the form response.
+// responseErrors returns an error if CCB returned errors in the payload.
+func responseErrors(ctx context.Context, data *ccbxml.Response) error {
 	if data.Response.Errors != nil && len(data.Response.Errors.Error) > 0 {
 		// FUTURE: Handle any specific errors needed here coming from CCB in the payload.
-		logger.WithField("errors", data.Response.Errors.Error).Error("Error returned from CCB.")
-		return nil, errors.New("errors returned from CCB response")
+		vouslog.GetLogger(ctx).WithField("errors", data.Response.Errors.Error).Error("Error returned from CCB.")
+		return errors.New("errors returned from CCB response")
 	}
+	return nil
+}
 
-	// Exit if there's no responses. This is fine for empty pages.
-	if data.Response.FormResponses == nil || data.Response.FormResponses.Count == 0 {
-		return nil, nil
-	}
+// formResponseFromCCB builds the FormResponse from a record of a form_responses
+// response. Timestamps are parsed in the time zone loc. Malformed records are kept
+// with warnings rather than failing the whole page.
+func formResponseFromCCB(ctx context.Context, v *ccbxml.FormResponse, contactFields ContactFields, loc *time.Location) FormResponse {
+	logger := vouslog.GetLogger(ctx)
 
-	// Build the FormResponses.
-	var formResponses []FormResponse
-	for _, v := range data.Response.FormResponses.FormResponse {
-		profInfo := map[string]string{} // this will contain profile information
-		answers := map[string]string{}  // this will contain the form questions and answers
+	var warnings []string
+	profInfo := map[string]string{} // this will contain profile information
 
-		// range over profile information and move to a map with info.Name as the key and info.Text as the value
+	// range over profile information and move to a map with info.Name as the key and info.Text as the value
+	if v.ProfileFields != nil {
 		for _, info := range v.ProfileFields.ProfileInfo {
-			profInfo[info.Name] = info.Text
+			if info != nil {
+				profInfo[info.Name] = info.Text
+			}
 		}
+	}
+
+	contact, contactWarnings := NormalizeContact(profInfo, contactFields)
+	warnings = append(warnings, contactWarnings...)
 
-		// range over XML unmarshalled "Answers" and move form questions and answers to a map
-		for i, t := range v.Answers.Title {
-			answers[t] = v.Answers.Choice[i]
+	// the answers keep the question order, and the map view is kept for compatibility
+	answerList := []Answer{}
+	if v.Answers != nil {
+		for _, a := range v.Answers.Answers {
+			answerList = append(answerList, Answer{Question: a.Question, Values: a.Values})
 		}
+		warnings = append(warnings, v.Answers.Warnings...)
+	}
+	answers, mapWarnings := answersMap(answerList)
+	warnings = append(warnings, mapWarnings...)
+
+	payment, paymentWarnings := paymentFromCCB(v.PaymentInfo)
+	warnings = append(warnings, paymentWarnings...)
 
-		// fill in the rest of the form data
-		f := FormResponse{
-			ID:          v.Form.ID,
-			ProfileInfo: profInfo,
-			Answers:     answers,
-			Created:     v.Created,
-			Modified:    v.Modified,
+	var formID string
+	if v.Form != nil {
+		formID = v.Form.ID
+	} else {
+		warnings = append(warnings, "missing form")
+	}
+
+	var individual *IndividualRef
+	if v.Individual != nil && v.Individual.ID != "" && v.Individual.ID != "0" {
+		individual = &IndividualRef{
+			ID:   v.Individual.ID,
+			Name: strings.TrimSpace(v.Individual.Name),
 		}
+	}
+
+	created, err := parseTimestamp(v.Created, loc)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("invalid created timestamp %q", v.Created))
+	}
+	modified, err := parseTimestamp(v.Modified, loc)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("invalid modified timestamp %q", v.Modified))
+	}
 
-		// append the Form Data to formResponses.Responses
-		formResponses = append(formResponses, f)
+	// fill in the rest of the form data
+	f := FormResponse{
+		ID:          v.ID,
+		FormID:      formID,
+		Individual:  individual,
+		ProfileInfo: profInfo,
+		Contact:     contact,
+		Answers:     answers,
+		AnswerList:  answerList,
+		Payment:     payment,
+		Created:     created,
+		Modified:    modified,
+		Warnings:    warnings,
 	}
-	return formResponses, nil
+
+	if len(warnings) > 0 {
+		logger.WithFields(logrus.Fields{
+			"form_response_id": v.ID,
+			"warnings":         warnings,
+		}).Warn("Malformed form response from CCB.")
+	}
+	return f
 }
 
 func (svc *defaultService) doRequestWithRetry(ctx context.Context, req *http.Request) (*http.Response, error) {
@@ -197,6 +456,7 @@ func (svc *defaultService) doRequestWithRetry(ctx context.Context, req *http.Req
 	expBackoff.InitialInterval = initialBackoffInterval
 	expBackoff.MaxElapsedTime = maxElapsedTime
 
+	srv := srvName(req)
 	var resp *http.Response
 	var retryRespStatusCode int
 	var retryCount int
@@ -208,6 +468,7 @@ func (svc *defaultService) doRequestWithRetry(ctx context.Context, req *http.Req
 		}
 
 		retryCount++
+		ccbRetriesTotal.Inc(srv)
 
 		if resp != nil {
 			retryRespStatusCode = resp.StatusCode
@@ -218,7 +479,7 @@ func (svc *defaultService) doRequestWithRetry(ctx context.Context, req *http.Req
 	}
 
 	logger := vouslog.GetLogger(ctx).WithFields(logrus.Fields{
-		"req_url": req.URL.String(),
+		"req_url": redactURL(req.URL),
 	})
 
 	if err := backoff.Retry(func() error {
@@ -226,30 +487,46 @@ func (svc *defaultService) doRequestWithRetry(ctx context.Context, req *http.Req
 		if retryCount > 0 {
 			logger.Data["retry_error"] = retryErr
 			logger.Data["retry_resp_status_code"] = retryRespStatusCode
-			if logger.Level >= logrus.DebugLevel {
+			if logger.Logger.IsLevelEnabled(logrus.DebugLevel) {
 				logger.Data["retry_resp"] = dumpResponse(resp)
 			}
 		}
 		logger.Info("Calling CCB service.")
+		ccbCallsTotal.Inc(srv)
 
+		// The timeout covers reading the body, so it's cancelled when the body of a
+		// successful response is closed rather than when this returns.
 		timedCtx, cancel := context.WithTimeout(ctx, svc.config.DefaultTimeout)
-		defer cancel() // Prevent a context-leak.
 		timedReq := req.WithContext(timedCtx)
 
 		var err error
 		if resp, err = svc.client.Do(timedReq); err != nil {
+			cancel()
 			// No retries on this type of failure coming from invocation.
 			logger.WithError(err).Info("Got permanent error from CCB service.")
 			return backoff.Permanent(err)
 		}
+		observeResponse(srv, resp)
 
-		// Retry on these specific status codes.
+		// Retry on these specific status codes. Changes may have been made on errors
+		// other than unavailable, so only reads are retried for them.
 		switch resp.StatusCode {
-		case http.StatusInternalServerError, http.StatusGatewayTimeout, http.StatusServiceUnavailable:
+		case http.StatusInternalServerError, http.StatusGatewayTimeout:
+			if req.Method == http.MethodGet {
+				resp.Body.Close()
+				cancel()
+				return handleRetryError(errors.New("unsuccessful response from CCB service"))
+			}
+		case http.StatusServiceUnavailable:
+			resp.Body.Close()
+			cancel()
 			return handleRetryError(errors.New("unsuccessful response from CCB service"))
 		}
 
-		logger.WithField("resp", dumpResponse(resp)).Debug("CCB service response.")
+		if logger.Logger.IsLevelEnabled(logrus.DebugLevel) {
+			logger.WithField("resp", dumpResponse(resp)).Debug("CCB service response.")
+		}
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
 		return nil
 	}, expBackoff); err != nil {
 		return nil, fmt.Errorf("failed to call CCB service after %d retries: %s", retryCount, err.Error())
@@ -258,6 +535,20 @@ func (svc *defaultService) doRequestWithRetry(ctx context.Context, req *http.Req
 	return resp, nil
 }
 
+// cancelOnClose is a response body which cancels the context of its request when
+// closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the request.
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
 // // makeCCBRequest performs a request against Church Community Build (CCB).
 // func makeCCBRequest(ctx iris.Context, url string, method string) (*CCBResponse, error) {
 // 	logger := vouslog.GetLogger(ctx.Request().Context())
@@ -310,22 +601,49 @@ func (svc *defaultService) doRequestWithRetry(ctx context.Context, req *http.Req
 // 	return &data, nil
 // }
 
-// dumpResponse returns a human readable string representing the request and response
+// redactURL returns the URL of a CCB request for logging, with the values of the
+// query replaced except for the service, ids and paging. Other parameters can be
+// details of individuals, such as the email and phone searched for or created.
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	for k, v := range q {
+		if k == "srv" || k == "id" || strings.HasSuffix(k, "_id") || k == "page" || k == "per_page" {
+			continue
+		}
+		for i := range v {
+			v[i] = "[redacted]"
+		}
+	}
+	redacted := *u
+	redacted.RawQuery = q.Encode()
+	return redacted.String()
+}
+
+// dumpResponse returns a human readable string representing the request and the
+// response headers. The body isn't dumped, since it would have to be buffered in
+// memory and pages of records can be large.
 func dumpResponse(resp *http.Response) string {
 	var (
 		reqBuf, respBuf []byte
 		err             error
 	)
 
+	if resp == nil {
+		return "Response\n<nil>\n"
+	}
 	if resp.Request != nil {
-		if reqBuf, err = httputil.DumpRequestOut(resp.Request, false); err != nil {
+		// Leave out the credentials and the details of individuals in the query.
+		req := resp.Request.Clone(resp.Request.Context())
+		req.Header.Del("Authorization")
+		if u, err := url.Parse(redactURL(req.URL)); err == nil {
+			req.URL = u
+		}
+		if reqBuf, err = httputil.DumpRequestOut(req, false); err != nil {
 			reqBuf = []byte(fmt.Sprintf("[ERROR: %s]", err.Error()))
 		}
 	}
-	if resp != nil {
-		if respBuf, err = httputil.DumpResponse(resp, true); err != nil {
-			respBuf = []byte(fmt.Sprintf("[ERROR: %s]", err.Error()))
-		}
+	if respBuf, err = httputil.DumpResponse(resp, false); err != nil {
+		respBuf = []byte(fmt.Sprintf("[ERROR: %s]", err.Error()))
 	}
 	return fmt.Sprintf("Request\n%s\n\n\nResponse\n%s\n", reqBuf, respBuf)
 }
@@ -409,170 +727,3 @@ func dumpResponse(resp *http.Response) string {
 // 	ctx.ContentType("application/json")
 // 	ctx.Write(jsonResponse)
 // }
-
-// CCBResponse represents the xml response from CCB individual search or CCB form response
-type ccbResponse struct {
-	Request struct {
-		Parameters struct {
-			Argument []struct {
-				Value string `xml:"value,attr,omitempty" json:"value,omitempty"`
-				Name  string `xml:"name,attr,omitempty" json:"name,omitempty"`
-			} `xml:"argument,omitempty" json:"argument,omitempty"`
-		} `xml:"parameters,omitempty" json:"parameters,omitempty"`
-	} `xml:"request,omitempty" json:"value,omitempty"`
-	Response struct {
-		Service       string `xml:"service,omitempty" json:"service,omitempty"`
-		ServiceAction string `xml:"service_action,omitempty" json:"service_action,omitempty"`
-		Availability  string `xml:"availability,omitempty" json:"availability,omitempty"`
-		Individuals   *struct {
-			Count      string `xml:"count,attr,omitempty" json:"count,omitempty"`
-			Individual *struct {
-				ID           string `xml:"id,attr,omitempty" json:"id,omitempty"`
-				GivingNumber string `xml:"giving_number,omitempty" json:"giving_number,omitempty"`
-				Campus       *struct {
-					ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-				} `xml:"campus,omitempty" json:"campus,omitempty"`
-				Family *struct {
-					ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-				} `xml:"family,omitempty" json:"family,omitempty"`
-				FamilyImage          string `xml:"family_image,omitempty" json:"family_image,omitempty"`
-				FamilyPosition       string `xml:"family_position,omitempty" json:"family_position,omitempty"`
-				FamilyMembers        string `xml:"family_members,omitempty" json:"family_members,omitempty"`
-				FirstName            string `xml:"first_name,omitempty" json:"first_name,omitempty"`
-				LastName             string `xml:"last_name,omitempty" json:"last_name,omitempty"`
-				MiddleName           string `xml:"middle_name,omitempty" json:"middle_name,omitempty"`
-				LegalFirstName       string `xml:"legal_first_name,omitempty" json:"legal_first_name,omitempty"`
-				FullName             string `xml:"full_name,omitempty" json:"full_name,omitempty"`
-				Salutation           string `xml:"salutation,omitempty" json:"salutation,omitempty"`
-				Suffix               string `xml:"suffix,omitempty" json:"suffix,omitempty"`
-				Image                string `xml:"image,omitempty" json:"image,omitempty"`
-				Email                string `xml:"email,omitempty" json:"email,omitempty"`
-				Allergies            string `xml:"allergies,omitempty" json:"allergies,omitempty"`
-				ConfirmedNoAllergies string `xml:"confirmed_no_allergies,omitempty" json:"confirmed_no_allergies,omitempty"`
-				Addresses            *struct {
-					Address []*struct {
-						Type          string `xml:"type,attr,omitempty" json:"type,attr,omitempty"`
-						StreetAddress string `xml:"street_address,omitempty" json:"street_address,omitempty"`
-						City          string `xml:"city,omitempty" json:"city,omitempty"`
-						State         string `xml:"state,omitempty" json:"state,omitempty"`
-						Zip           string `xml:"zip,omitempty" json:"zip,omitempty"`
-						Country       *struct {
-							Code string `xml:"code,attr,omitempty" json:"code,omitempty"`
-						} `xml:"country,omitempty" json:"country,omitempty"`
-						Line1     string `xml:"line_1,omitempty" json:"line_1,omitempty"`
-						Line2     string `xml:"line_2,omitempty" json:"line_2,omitempty"`
-						Latitude  string `xml:"latitude,omitempty" json:"latitude,omitempty"`
-						Longitude string `xml:"longitude,omitempty" json:"longitude,omitempty"`
-					} `xml:"address,omitempty" json:"address,omitempty"`
-				} `xml:"addresses,omitempty" json:"addresses,omitempty"`
-				Phones []*struct {
-					Type   string `xml:"type,attr,omitempty" json:"type,omitempty"`
-					Number string `xml:",chardata" json:"number,omitempty"`
-				} `xml:"phones>phone,omitempty" json:"phones,omitempty"`
-				MobileCarrier *struct {
-					ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-				} `xml:"mobile_carrier,omitempty" json:"mobile_carrier,omitempty"`
-				Gender         string `xml:"gender,omitempty" json:"gender,omitempty"`
-				MaritalStatus  string `xml:"marital_status,omitempty" json:"marital_status,omitempty"`
-				Birthday       string `xml:"birthday,omitempty" json:"birthday,omitempty"`
-				Anniversary    string `xml:"anniversary,omitempty" json:"anniversary,omitempty"`
-				Baptized       string `xml:"baptized,omitempty" json:"baptized,omitempty"`
-				Deceased       string `xml:"deceased,omitempty" json:"deceased,omitempty"`
-				MembershipType *struct {
-					ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-				} `xml:"membership_type,omitempty" json:"membership_type,omitempty"`
-				MembershipDate          string `xml:"membership_date,omitempty" json:"membership_date,omitempty"`
-				MembershipEnd           string `xml:"membership_end,omitempty" json:"membership_end,omitempty"`
-				ReceiveEmailFromChurch  string `xml:"receive_email_from_church,omitempty" json:"receive_email_from_church,omitempty"`
-				DefaultNewGroupMessages string `xml:"default_new_group_messages,omitempty" json:"default_new_group_messages,omitempty"`
-				DefaultNewGroupComments string `xml:"default_new_group_comments,omitempty" json:"default_new_group_comments,omitempty"`
-				DefaultNewGroupDigest   string `xml:"default_new_group_digest,omitempty" json:"default_new_group_digest,omitempty"`
-				DefaultNewGroupSms      string `xml:"default_new_group_sms,omitempty" json:"default_new_group_sms,omitempty"`
-				PrivacySettings         *struct {
-					ProfileListed  string `xml:"profile_listed,omitempty" json:"profile_listed,omitempty"`
-					MailingAddress *struct {
-						ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-					} `xml:"mailing_address,omitempty" json:"mailing_address,omitempty"`
-					HomeAddress *struct {
-						ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-					} `xml:"home_address,omitempty" json:"home_address,omitempty"`
-					HomePhone *struct {
-						ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-					} `xml:"home_phone,omitempty" json:"home_phone,omitempty"`
-					WorkPhone *struct {
-						ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-					} `xml:"work_phone,omitempty" json:"work_phone,omitempty"`
-					MobilePhone *struct {
-						ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-					} `xml:"mobile_phone,omitempty" json:"mobile_phone,omitempty"`
-					EmergencyPhone *struct {
-						ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-					} `xml:"emergency_phone,omitempty" json:"emergency_phone,omitempty"`
-					Birthday *struct {
-						ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-					} `xml:"birthday,omitempty" json:"birthday,omitempty"`
-					Anniversary *struct {
-						ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-					} `xml:"anniversary,omitempty" json:"anniversary,omitempty"`
-					Gender *struct {
-						ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-					} `xml:"gender,omitempty" json:"gender,omitempty"`
-					MaritalStatus *struct {
-						ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-					} `xml:"marital_status,omitempty" json:"marital_status,omitempty"`
-					UserDefinedFields *struct {
-						ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-					} `xml:"user_defined_fields,omitempty" json:"user_defined_fields,omitempty"`
-					Allergies *struct {
-						ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-					} `xml:"allergies,omitempty" json:"allergies,omitempty"`
-				} `xml:"privacy_settings,omitempty" json:"privacy_settings,omitempty"`
-				Active  string `xml:"active,omitempty" json:"active,omitempty"`
-				Creator *struct {
-					ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-				} `xml:"creator,omitempty" json:"creator,omitempty"`
-				Modifier *struct {
-					ID string `xml:"id,attr,omitempty" json:"id,omitempty"`
-				} `xml:"modifier,omitempty" json:"modifier,omitempty"`
-				Created                   string `xml:"created,omitempty" json:"created,omitempty"`
-				Modified                  string `xml:"modified,omitempty" json:"modified,omitempty"`
-				UserDefinedTextFields     string `xml:"user_defined_text_fields,omitempty" json:"user_defined_text_fields,omitempty"`
-				UserDefinedDateFields     string `xml:"user_defined_date_fields,omitempty" json:"user_defined_date_fields,omitempty"`
-				UserDefinedPulldownFields string `xml:"user_defined_pulldown_fields,omitempty" json:"user_defined_pulldown_fields,omitempty"`
-			} `xml:"individual,omitempty" json:"individual,omitempty"`
-		} `xml:"individuals,omitempty" json:"individuals,omitempty"`
-		FormResponses *struct {
-			Count        int `xml:"count,attr,omitempty" json:"count,omitempty"`
-			FormResponse []*struct {
-				ID   string `xml:"id,attr,omitempty" json:"id,omitempty"`
-				Form *struct {
-					ID string `xml:"id,attr" json:"id,omitempty"`
-				} `xml:"form,omitempty" json:"form,omitempty"`
-				Individual *struct {
-					Name string `xml:",chardata" json:"name,omitempty"`
-					ID   string `xml:"id,attr,omitempty" json:"id,omitempty"`
-				} `xml:"individual,omitempty" json:"individual,omitempty"`
-				Created       string `xml:"created,omitempty" json:"created,omitempty"`
-				Modified      string `xml:"modified,omitempty" json:"modified,omitempty"`
-				ProfileFields *struct {
-					ProfileInfo []*struct {
-						Name string `xml:"name,attr,omitempty" json:"name,omitempty"`
-						Text string `xml:",chardata" json:"text,omitempty"`
-					} `xml:"profile_info,omitempty" json:"profile_info,omitempty"`
-				} `xml:"profile_fields,omitempty" json:"profile_fields,omitempty"`
-				Answers *struct {
-					Title  []string `xml:"title,omitempty" json:"title,omitempty"`
-					Choice []string `xml:"choice,omitempty" json:"choice,omitempty"`
-				} `xml:"answers,omitempty" json:"answers,omitempty"`
-				PaymentInfo string `xml:"payment_info,omitempty"  json:"payment_info,omitempty"`
-			} `xml:"form_response,omitempty" json:"form_response,omitempty"`
-		} `xml:"form_responses,omitempty" json:"form_responses,omitempty"`
-		Errors *struct {
-			Error []struct {
-				Number  string `xml:"number,attr,omitempty" json:"number,omitempty"`
-				Type    string `xml:"type,attr,omitempty" json:"type,omitempty"`
-				Message string `xml:",chardata" json:"message,omitempty"`
-			} `xml:"error,omitempty" json:"errors,omitempty"`
-		} `xml:"errors,omitempty" json:"errors,omitempty"`
-	} `xml:"response,omitempty" json:"response,omitempty"`
-}
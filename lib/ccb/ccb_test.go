@@ -0,0 +1,18 @@
+package ccb
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestRedactURL(t *testing.T) {
+	u, err := url.Parse("https://example.ccbchurch.com/api.php?srv=individual_search&email=jane%40example.com&first_name=Jane&group_id=7&page=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "https://example.ccbchurch.com/api.php?email=%5Bredacted%5D&first_name=%5Bredacted%5D&group_id=7&page=2&srv=individual_search"
+	if got := redactURL(u); got != want {
+		t.Errorf("redactURL() = %q, want %q", got, want)
+	}
+}
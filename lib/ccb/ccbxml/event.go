@@ -0,0 +1,45 @@
+package ccbxml
+
+// Events is the list of events in event_profile and attendance_profiles
+// responses.
+type Events struct {
+	Count int      `xml:"count,attr,omitempty" json:"count,omitempty"`
+	Event []*Event `xml:"event,omitempty" json:"event,omitempty"`
+}
+
+// Event is an event, or the attendance of an occurrence of an event in
+// attendance_profiles responses.
+type Event struct {
+	ID                    string       `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name                  string       `xml:"name,omitempty" json:"name,omitempty"`
+	Description           string       `xml:"description,omitempty" json:"description,omitempty"`
+	StartDateTime         string       `xml:"start_datetime,omitempty" json:"start_datetime,omitempty"`
+	EndDateTime           string       `xml:"end_datetime,omitempty" json:"end_datetime,omitempty"`
+	RecurrenceDescription string       `xml:"recurrence_description,omitempty" json:"recurrence_description,omitempty"`
+	Group                 NamedRef     `xml:"group,omitempty" json:"group,omitempty"`
+	Organizer             NamedRef     `xml:"organizer,omitempty" json:"organizer,omitempty"`
+	Exceptions            []*Exception `xml:"exceptions>exception,omitempty" json:"exceptions,omitempty"`
+	Location              *Address     `xml:"location,omitempty" json:"location,omitempty"`
+	PublicCalendarListed  string       `xml:"public_calendar_listed,omitempty" json:"public_calendar_listed,omitempty"`
+	Created               string       `xml:"created,omitempty" json:"created,omitempty"`
+	Modified              string       `xml:"modified,omitempty" json:"modified,omitempty"`
+
+	// Attendance of an occurrence, in attendance_profiles responses.
+	Occurrence string      `xml:"occurrence,attr,omitempty" json:"occurrence,omitempty"`
+	DidNotMeet string      `xml:"did_not_meet,omitempty" json:"did_not_meet,omitempty"`
+	HeadCount  string      `xml:"head_count,omitempty" json:"head_count,omitempty"`
+	Topic      string      `xml:"topic,omitempty" json:"topic,omitempty"`
+	Notes      string      `xml:"notes,omitempty" json:"notes,omitempty"`
+	Attendees  []*Attendee `xml:"attendees>attendee,omitempty" json:"attendees,omitempty"`
+}
+
+// Exception is a date a recurring event does not occur on.
+type Exception struct {
+	Date string `xml:"date,omitempty" json:"date,omitempty"`
+}
+
+// Attendee is an individual who attended an occurrence of an event.
+type Attendee struct {
+	ID   string `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name string `xml:"name,omitempty" json:"name,omitempty"`
+}
@@ -0,0 +1,44 @@
+package ccbxml
+
+import "testing"
+
+func TestEvent(t *testing.T) {
+	resp, _ := roundTrip(t, "event_profile.xml")
+
+	events := resp.Response.Events
+	if events == nil || len(events.Event) != 1 {
+		t.Fatalf("events = %+v, want one", events)
+	}
+	e := events.Event[0]
+	if e.ID != "7" || e.StartDateTime != "2019-01-06 10:00:00" || e.RecurrenceDescription != "Every week on Sunday" {
+		t.Errorf("event = %+v", e)
+	}
+	if e.Group != (NamedRef{ID: "1", Name: "Church"}) || e.Organizer.Name != "Pastor" {
+		t.Errorf("event = %+v", e)
+	}
+	if len(e.Exceptions) != 1 || e.Exceptions[0].Date != "2026-12-27" {
+		t.Errorf("exceptions = %+v", e.Exceptions)
+	}
+	if e.Location == nil || e.Location.Name != "Main Auditorium" || e.Location.City != "Miami" {
+		t.Errorf("location = %+v", e.Location)
+	}
+}
+
+func TestAttendance(t *testing.T) {
+	resp, _ := roundTrip(t, "attendance_profiles.xml")
+
+	events := resp.Response.Events
+	if events == nil || len(events.Event) != 2 {
+		t.Fatalf("events = %+v, want two", events)
+	}
+	e := events.Event[0]
+	if e.Occurrence != "2026-10-11 10:00:00" || e.DidNotMeet != "false" || e.HeadCount != "14" || e.Topic != "Step 1" {
+		t.Errorf("attendance = %+v", e)
+	}
+	if len(e.Attendees) != 2 || *e.Attendees[1] != (Attendee{ID: "6", Name: "John Doe"}) {
+		t.Errorf("attendees = %+v", e.Attendees)
+	}
+	if e := events.Event[1]; e.DidNotMeet != "true" || len(e.Attendees) != 0 {
+		t.Errorf("attendance = %+v", e)
+	}
+}
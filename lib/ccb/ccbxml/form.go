@@ -0,0 +1,169 @@
+package ccbxml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// FormResponses is the list of form responses in form_responses responses.
+type FormResponses struct {
+	Count        int             `xml:"count,attr,omitempty" json:"count,omitempty"`
+	FormResponse []*FormResponse `xml:"form_response,omitempty" json:"form_response,omitempty"`
+}
+
+// FormResponse is a response to a form such as a Connect Card.
+type FormResponse struct {
+	ID            string         `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Form          *NamedRef      `xml:"form,omitempty" json:"form,omitempty"`
+	Individual    *NamedRef      `xml:"individual,omitempty" json:"individual,omitempty"`
+	Created       string         `xml:"created,omitempty" json:"created,omitempty"`
+	Modified      string         `xml:"modified,omitempty" json:"modified,omitempty"`
+	ProfileFields *ProfileFields `xml:"profile_fields,omitempty" json:"profile_fields,omitempty"`
+	Answers       *Answers       `xml:"answers,omitempty" json:"answers,omitempty"`
+	PaymentInfo   *PaymentInfo   `xml:"payment_info,omitempty" json:"payment_info,omitempty"`
+}
+
+// ProfileFields are the profile fields filled in on a form.
+type ProfileFields struct {
+	ProfileInfo []*ProfileInfo `xml:"profile_info,omitempty" json:"profile_info,omitempty"`
+}
+
+// ProfileInfo is a profile field filled in on a form, e.g.
+// <profile_info name="Email">jane@example.com</profile_info>.
+type ProfileInfo struct {
+	Name string `xml:"name,attr,omitempty" json:"name,omitempty"`
+	Text string `xml:",chardata" json:"text,omitempty"`
+}
+
+// Answer is the answer to a question on a form. Multiple choice questions can have
+// several values, and unanswered questions have none.
+type Answer struct {
+	Question string   `json:"question"`
+	Values   []string `json:"values"`
+}
+
+// Answers are the answers of a form response. CCB returns them as a flat list
+// where each <title> is followed by the <choice> elements answering it, so the
+// order of the elements is significant.
+type Answers struct {
+	Answers  []Answer
+	Warnings []string `xml:"-" json:"-"` // Problems with the answers which did not stop decoding.
+}
+
+// UnmarshalXML implements xml.Unmarshaler.
+func (a *Answers) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return nil // End of the answers element.
+		case xml.StartElement:
+			var text string
+			if err := d.DecodeElement(&text, &t); err != nil {
+				return err
+			}
+
+			switch t.Name.Local {
+			case "title":
+				a.Answers = append(a.Answers, Answer{Question: strings.TrimSpace(text), Values: []string{}})
+			case "choice":
+				if len(a.Answers) == 0 {
+					a.Warnings = append(a.Warnings, fmt.Sprintf("answer %q has no question", text))
+					continue
+				}
+				last := &a.Answers[len(a.Answers)-1]
+				last.Values = append(last.Values, text)
+			default:
+				a.Warnings = append(a.Warnings, fmt.Sprintf("unexpected element %q in answers", t.Name.Local))
+			}
+		}
+	}
+}
+
+// MarshalXML implements xml.Marshaler, writing the answers back as a flat list.
+func (a *Answers) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, answer := range a.Answers {
+		if err := e.EncodeElement(answer.Question, xml.StartElement{Name: xml.Name{Local: "title"}}); err != nil {
+			return err
+		}
+		for _, v := range answer.Values {
+			if err := e.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: "choice"}}); err != nil {
+				return err
+			}
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// PaymentInfo is the payment_info element of a form response, which is empty for
+// forms without payments. The children are collected by name so that the aliases
+// CCB uses for the same value can all be accepted.
+type PaymentInfo struct {
+	Fields map[string]string
+}
+
+// UnmarshalXML implements xml.Unmarshaler.
+func (p *PaymentInfo) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	p.Fields = map[string]string{}
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return nil // End of the payment_info element.
+		case xml.StartElement:
+			var text string
+			if err := d.DecodeElement(&text, &t); err != nil {
+				return err
+			}
+			p.Fields[t.Name.Local] = strings.TrimSpace(text)
+		}
+	}
+}
+
+// MarshalXML implements xml.Marshaler, writing the fields in name order.
+func (p *PaymentInfo) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(p.Fields))
+	for name := range p.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := e.EncodeElement(p.Fields[name], xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// Field returns the first non-empty value of the aliases.
+func (p *PaymentInfo) Field(aliases ...string) string {
+	for _, a := range aliases {
+		if v := p.Fields[a]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package ccbxml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormResponses(t *testing.T) {
+	resp, _ := roundTrip(t, "form_responses.xml")
+
+	responses := resp.Response.FormResponses
+	if responses == nil || responses.Count != 2 || len(responses.FormResponse) != 2 {
+		t.Fatalf("form responses = %+v, want two", responses)
+	}
+	r := responses.FormResponse[0]
+	if r.ID != "1001" || r.Form == nil || r.Form.ID != "85" || r.Individual == nil || r.Individual.Name != "Jane Doe" {
+		t.Errorf("form response = %+v", r)
+	}
+	if r.ProfileFields == nil || len(r.ProfileFields.ProfileInfo) != 3 ||
+		*r.ProfileFields.ProfileInfo[1] != (ProfileInfo{Name: "Email", Text: "Jane@Example.com"}) {
+		t.Errorf("profile fields = %+v", r.ProfileFields)
+	}
+
+	if r := responses.FormResponse[1]; r.ProfileFields.ProfileInfo[0].Text != "Sam & Alex" {
+		t.Errorf("profile info = %+v", r.ProfileFields.ProfileInfo[0])
+	}
+}
+
+func TestAnswers(t *testing.T) {
+	resp, _ := roundTrip(t, "form_responses.xml")
+
+	answers := resp.Response.FormResponses.FormResponse[0].Answers
+	if answers == nil {
+		t.Fatal("answers = nil")
+	}
+	want := []Answer{
+		{Question: "Campus", Values: []string{"JDD"}},
+		{Question: "Next steps", Values: []string{"I'd like to get baptized", "Join a group"}},
+		{Question: "Prayer", Values: []string{}},
+	}
+	if !reflect.DeepEqual(answers.Answers, want) {
+		t.Errorf("answers = %+v, want %+v", answers.Answers, want)
+	}
+	if len(answers.Warnings) != 0 {
+		t.Errorf("warnings = %v, want none", answers.Warnings)
+	}
+}
+
+func TestPaymentInfo(t *testing.T) {
+	resp, _ := roundTrip(t, "form_responses.xml")
+
+	p := resp.Response.FormResponses.FormResponse[0].PaymentInfo
+	if p == nil {
+		t.Fatal("payment info = nil")
+	}
+	if got := p.Field("amount_paid", "paid"); got != "50" {
+		t.Errorf("amount paid = %q, want 50", got)
+	}
+	if got := p.Field("coupon", "coupon_code"); got != "EARLY" {
+		t.Errorf("coupon = %q, want EARLY", got)
+	}
+
+	// Forms without payments have an empty element.
+	empty := resp.Response.FormResponses.FormResponse[1].PaymentInfo
+	if empty == nil || len(empty.Fields) != 0 {
+		t.Errorf("payment info = %+v, want empty", empty)
+	}
+}
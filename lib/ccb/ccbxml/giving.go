@@ -0,0 +1,51 @@
+package ccbxml
+
+// Batches is the list of batches in batch_profiles and
+// batch_profiles_in_date_range responses.
+type Batches struct {
+	Count int      `xml:"count,attr,omitempty" json:"count,omitempty"`
+	Batch []*Batch `xml:"batch,omitempty" json:"batch,omitempty"`
+}
+
+// Batch is a batch of giving transactions. Who gave is deliberately not modelled,
+// so it cannot be decoded and leak into the API.
+type Batch struct {
+	ID           string         `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Campus       NamedRef       `xml:"campus,omitempty" json:"campus,omitempty"`
+	PostDate     string         `xml:"post_date,omitempty" json:"post_date,omitempty"`
+	Status       string         `xml:"status,omitempty" json:"status,omitempty"`
+	Source       string         `xml:"source,omitempty" json:"source,omitempty"`
+	Transactions []*Transaction `xml:"transactions>transaction,omitempty" json:"transactions,omitempty"`
+}
+
+// Transaction is a gift in a batch.
+type Transaction struct {
+	ID          string               `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Campus      NamedRef             `xml:"campus,omitempty" json:"campus,omitempty"`
+	Date        string               `xml:"date,omitempty" json:"date,omitempty"`
+	PaymentType string               `xml:"payment_type,omitempty" json:"payment_type,omitempty"`
+	Details     []*TransactionDetail `xml:"transaction_details>transaction_detail,omitempty" json:"transaction_details,omitempty"`
+}
+
+// TransactionDetail is the part of a transaction given to a fund.
+type TransactionDetail struct {
+	Fund          NamedRef `xml:"coa,omitempty" json:"coa,omitempty"`
+	Amount        string   `xml:"amount,omitempty" json:"amount,omitempty"`
+	TaxDeductible string   `xml:"tax_deductible,omitempty" json:"tax_deductible,omitempty"`
+}
+
+// TransactionDetailTypes is the list of funds in transaction_detail_type_list
+// responses.
+type TransactionDetailTypes struct {
+	Count                 int                      `xml:"count,attr,omitempty" json:"count,omitempty"`
+	TransactionDetailType []*TransactionDetailType `xml:"transaction_detail_type,omitempty" json:"transaction_detail_type,omitempty"`
+}
+
+// TransactionDetailType is a fund.
+type TransactionDetailType struct {
+	ID            string   `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name          string   `xml:"name,omitempty" json:"name,omitempty"`
+	Parent        NamedRef `xml:"parent,omitempty" json:"parent,omitempty"`
+	TaxDeductible string   `xml:"tax_deductible,omitempty" json:"tax_deductible,omitempty"`
+	Active        string   `xml:"active,omitempty" json:"active,omitempty"`
+}
@@ -0,0 +1,44 @@
+package ccbxml
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBatches(t *testing.T) {
+	resp, encoded := roundTrip(t, "batch_profiles_in_date_range.xml")
+
+	batches := resp.Response.Batches
+	if batches == nil || batches.Count != 2 || len(batches.Batch) != 2 {
+		t.Fatalf("batches = %+v, want two", batches)
+	}
+	b := batches.Batch[0]
+	if b.ID != "10" || b.Campus.Name != "Brooklyn" || b.PostDate != "2026-10-12" || len(b.Transactions) != 2 {
+		t.Fatalf("batch = %+v", b)
+	}
+	tx := b.Transactions[0]
+	if tx.Date != "2026-10-11" || tx.PaymentType != "Credit Card" || len(tx.Details) != 2 {
+		t.Fatalf("transaction = %+v", tx)
+	}
+	want := TransactionDetail{Fund: NamedRef{ID: "4", Name: "Missions"}, Amount: "25.50", TaxDeductible: "true"}
+	if *tx.Details[1] != want {
+		t.Errorf("detail = %+v, want %+v", *tx.Details[1], want)
+	}
+
+	// Who gave is not modelled, so it does not survive decoding.
+	if bytes.Contains(encoded, []byte("Donor")) {
+		t.Errorf("encoded batches include who gave:\n%s", encoded)
+	}
+}
+
+func TestTransactionDetailTypes(t *testing.T) {
+	resp, _ := roundTrip(t, "transaction_detail_type_list.xml")
+
+	types := resp.Response.TransactionDetailTypes
+	if types == nil || len(types.TransactionDetailType) != 2 {
+		t.Fatalf("transaction detail types = %+v, want two", types)
+	}
+	if got := *types.TransactionDetailType[1]; got.ID != "4" || got.Name != "Missions" || got.Parent.ID != "3" || got.Active != "true" {
+		t.Errorf("transaction detail type = %+v", got)
+	}
+}
@@ -0,0 +1,56 @@
+package ccbxml
+
+// Groups is the list of groups in group_profiles, group_profile_from_id and
+// group_participants responses.
+type Groups struct {
+	Count int      `xml:"count,attr,omitempty" json:"count,omitempty"`
+	Group []*Group `xml:"group,omitempty" json:"group,omitempty"`
+}
+
+// Group is a group such as a small group. group_participants only returns the id
+// and Participants.
+type Group struct {
+	ID                 string         `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name               string         `xml:"name,omitempty" json:"name,omitempty"`
+	Description        string         `xml:"description,omitempty" json:"description,omitempty"`
+	Campus             NamedRef       `xml:"campus,omitempty" json:"campus,omitempty"`
+	MainLeader         *Leader        `xml:"main_leader,omitempty" json:"main_leader,omitempty"`
+	CurrentMembers     string         `xml:"current_members,omitempty" json:"current_members,omitempty"`
+	GroupCapacity      string         `xml:"group_capacity,omitempty" json:"group_capacity,omitempty"`
+	Addresses          []*Address     `xml:"addresses>address,omitempty" json:"addresses,omitempty"`
+	MeetingDay         NamedRef       `xml:"meeting_day,omitempty" json:"meeting_day,omitempty"`
+	MeetingTime        NamedRef       `xml:"meeting_time,omitempty" json:"meeting_time,omitempty"`
+	ChildcareProvided  string         `xml:"childcare_provided,omitempty" json:"childcare_provided,omitempty"`
+	InteractionType    string         `xml:"interaction_type,omitempty" json:"interaction_type,omitempty"`
+	MembershipType     NamedRef       `xml:"membership_type,omitempty" json:"membership_type,omitempty"`
+	Listed             string         `xml:"listed,omitempty" json:"listed,omitempty"`
+	PublicSearchListed string         `xml:"public_search_listed,omitempty" json:"public_search_listed,omitempty"`
+	Inactive           string         `xml:"inactive,omitempty" json:"inactive,omitempty"`
+	GroupType          NamedRef       `xml:"group_type,omitempty" json:"group_type,omitempty"`
+	Department         NamedRef       `xml:"department,omitempty" json:"department,omitempty"`
+	Area               NamedRef       `xml:"area,omitempty" json:"area,omitempty"`
+	Participants       []*Participant `xml:"participants>participant,omitempty" json:"participants,omitempty"`
+	Created            string         `xml:"created,omitempty" json:"created,omitempty"`
+	Modified           string         `xml:"modified,omitempty" json:"modified,omitempty"`
+}
+
+// Leader is the main leader of a group.
+type Leader struct {
+	ID       string   `xml:"id,attr,omitempty" json:"id,omitempty"`
+	FullName string   `xml:"full_name,omitempty" json:"full_name,omitempty"`
+	Email    string   `xml:"email,omitempty" json:"email,omitempty"`
+	Phones   []*Phone `xml:"phones>phone,omitempty" json:"phones,omitempty"`
+}
+
+// Participant is a member of a group in group_participants responses.
+type Participant struct {
+	ID        string   `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name      string   `xml:"name,omitempty" json:"name,omitempty"`
+	FirstName string   `xml:"first_name,omitempty" json:"first_name,omitempty"`
+	LastName  string   `xml:"last_name,omitempty" json:"last_name,omitempty"`
+	Email     string   `xml:"email,omitempty" json:"email,omitempty"`
+	Phones    []*Phone `xml:"phones>phone,omitempty" json:"phones,omitempty"`
+	Status    string   `xml:"status,omitempty" json:"status,omitempty"`
+	Created   string   `xml:"created,omitempty" json:"created,omitempty"`
+	Modified  string   `xml:"modified,omitempty" json:"modified,omitempty"`
+}
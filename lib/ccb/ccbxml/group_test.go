@@ -0,0 +1,46 @@
+package ccbxml
+
+import "testing"
+
+func TestGroups(t *testing.T) {
+	resp, _ := roundTrip(t, "group_profiles.xml")
+
+	groups := resp.Response.Groups
+	if groups == nil || groups.Count != 3 || len(groups.Group) != 3 {
+		t.Fatalf("groups = %+v, want three", groups)
+	}
+	g := groups.Group[0]
+	if g.ID != "10" || g.Name != "Tuesday Young Adults" || g.Campus != (NamedRef{ID: "2", Name: "Johnson Drive District"}) {
+		t.Errorf("group = %+v", g)
+	}
+	if g.MainLeader == nil || g.MainLeader.FullName != "Jane Leader" || len(g.MainLeader.Phones) != 1 {
+		t.Errorf("main leader = %+v", g.MainLeader)
+	}
+	if len(g.Addresses) != 1 || g.Addresses[0].Type != "meeting" || g.Addresses[0].Zip != "33101" {
+		t.Errorf("addresses = %+v", g.Addresses)
+	}
+	if g.MeetingDay.Name != "Tuesday" || g.MeetingTime.Name != "7:00 PM" || g.GroupType.Name != "Small Group" || g.Area.Name != "Downtown" {
+		t.Errorf("group = %+v", g)
+	}
+	if g.CurrentMembers != "12" || g.GroupCapacity != "12" || g.Inactive != "false" {
+		t.Errorf("group = %+v", g)
+	}
+}
+
+func TestGroupParticipants(t *testing.T) {
+	resp, _ := roundTrip(t, "group_participants.xml")
+
+	if resp.Response.Groups == nil || len(resp.Response.Groups.Group) != 1 {
+		t.Fatalf("groups = %+v, want one", resp.Response.Groups)
+	}
+	participants := resp.Response.Groups.Group[0].Participants
+	if len(participants) != 2 {
+		t.Fatalf("participants = %+v, want two", participants)
+	}
+	if p := participants[0]; p.ID != "50" || p.Status != "Leader" || p.Created != "2019-01-02 10:00:00" {
+		t.Errorf("participant = %+v", p)
+	}
+	if p := participants[1]; len(p.Phones) != 1 || p.Phones[0].Number != "555-0101" {
+		t.Errorf("participant = %+v", p)
+	}
+}
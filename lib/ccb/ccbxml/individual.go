@@ -0,0 +1,137 @@
+package ccbxml
+
+// Individuals is the list of individuals in individual_profiles,
+// individual_profile_from_id, individual_search, individual_significant_events and
+// individual_groups responses. Searches return many individuals.
+type Individuals struct {
+	Count      int           `xml:"count,attr,omitempty" json:"count,omitempty"`
+	Individual []*Individual `xml:"individual,omitempty" json:"individual,omitempty"`
+}
+
+// Individual is a person. Services only return some of the fields, e.g.
+// individual_groups only returns the id and Groups.
+type Individual struct {
+	ID                      string           `xml:"id,attr,omitempty" json:"id,omitempty"`
+	GivingNumber            string           `xml:"giving_number,omitempty" json:"giving_number,omitempty"`
+	Campus                  *NamedRef        `xml:"campus,omitempty" json:"campus,omitempty"`
+	Family                  *NamedRef        `xml:"family,omitempty" json:"family,omitempty"`
+	FamilyImage             string           `xml:"family_image,omitempty" json:"family_image,omitempty"`
+	FamilyPosition          string           `xml:"family_position,omitempty" json:"family_position,omitempty"`
+	FamilyMembers           []*FamilyMember  `xml:"family_members>family_member,omitempty" json:"family_members,omitempty"`
+	FirstName               string           `xml:"first_name,omitempty" json:"first_name,omitempty"`
+	LastName                string           `xml:"last_name,omitempty" json:"last_name,omitempty"`
+	MiddleName              string           `xml:"middle_name,omitempty" json:"middle_name,omitempty"`
+	LegalFirstName          string           `xml:"legal_first_name,omitempty" json:"legal_first_name,omitempty"`
+	FullName                string           `xml:"full_name,omitempty" json:"full_name,omitempty"`
+	Salutation              string           `xml:"salutation,omitempty" json:"salutation,omitempty"`
+	Suffix                  string           `xml:"suffix,omitempty" json:"suffix,omitempty"`
+	Image                   string           `xml:"image,omitempty" json:"image,omitempty"`
+	Email                   string           `xml:"email,omitempty" json:"email,omitempty"`
+	Allergies               string           `xml:"allergies,omitempty" json:"allergies,omitempty"`
+	ConfirmedNoAllergies    string           `xml:"confirmed_no_allergies,omitempty" json:"confirmed_no_allergies,omitempty"`
+	Addresses               []*Address       `xml:"addresses>address,omitempty" json:"addresses,omitempty"`
+	Phones                  []*Phone         `xml:"phones>phone,omitempty" json:"phones,omitempty"`
+	MobileCarrier           *NamedRef        `xml:"mobile_carrier,omitempty" json:"mobile_carrier,omitempty"`
+	Gender                  string           `xml:"gender,omitempty" json:"gender,omitempty"`
+	MaritalStatus           string           `xml:"marital_status,omitempty" json:"marital_status,omitempty"`
+	Birthday                string           `xml:"birthday,omitempty" json:"birthday,omitempty"`
+	Anniversary             string           `xml:"anniversary,omitempty" json:"anniversary,omitempty"`
+	Baptized                string           `xml:"baptized,omitempty" json:"baptized,omitempty"`
+	Deceased                string           `xml:"deceased,omitempty" json:"deceased,omitempty"`
+	MembershipType          *NamedRef        `xml:"membership_type,omitempty" json:"membership_type,omitempty"`
+	MembershipDate          string           `xml:"membership_date,omitempty" json:"membership_date,omitempty"`
+	MembershipEnd           string           `xml:"membership_end,omitempty" json:"membership_end,omitempty"`
+	ReceiveEmailFromChurch  string           `xml:"receive_email_from_church,omitempty" json:"receive_email_from_church,omitempty"`
+	DefaultNewGroupMessages string           `xml:"default_new_group_messages,omitempty" json:"default_new_group_messages,omitempty"`
+	DefaultNewGroupComments string           `xml:"default_new_group_comments,omitempty" json:"default_new_group_comments,omitempty"`
+	DefaultNewGroupDigest   string           `xml:"default_new_group_digest,omitempty" json:"default_new_group_digest,omitempty"`
+	DefaultNewGroupSms      string           `xml:"default_new_group_sms,omitempty" json:"default_new_group_sms,omitempty"`
+	PrivacySettings         *PrivacySettings `xml:"privacy_settings,omitempty" json:"privacy_settings,omitempty"`
+	Active                  string           `xml:"active,omitempty" json:"active,omitempty"`
+	Creator                 *NamedRef        `xml:"creator,omitempty" json:"creator,omitempty"`
+	Modifier                *NamedRef        `xml:"modifier,omitempty" json:"modifier,omitempty"`
+	Created                 string           `xml:"created,omitempty" json:"created,omitempty"`
+	Modified                string           `xml:"modified,omitempty" json:"modified,omitempty"`
+
+	UserDefinedTextFields     []*CustomField `xml:"user_defined_text_fields>user_defined_text_field,omitempty" json:"user_defined_text_fields,omitempty"`
+	UserDefinedDateFields     []*CustomField `xml:"user_defined_date_fields>user_defined_date_field,omitempty" json:"user_defined_date_fields,omitempty"`
+	UserDefinedPulldownFields []*CustomField `xml:"user_defined_pulldown_fields>user_defined_pulldown_field,omitempty" json:"user_defined_pulldown_fields,omitempty"`
+
+	SignificantEvents []*SignificantEvent `xml:"significant_events>significant_event,omitempty" json:"significant_events,omitempty"`
+	Groups            []*IndividualGroup  `xml:"groups>group,omitempty" json:"groups,omitempty"`
+}
+
+// PrivacySettings are who can see each part of the profile of an individual, e.g.
+// <mailing_address id="3">Everyone</mailing_address>.
+type PrivacySettings struct {
+	ProfileListed     string    `xml:"profile_listed,omitempty" json:"profile_listed,omitempty"`
+	MailingAddress    *NamedRef `xml:"mailing_address,omitempty" json:"mailing_address,omitempty"`
+	HomeAddress       *NamedRef `xml:"home_address,omitempty" json:"home_address,omitempty"`
+	HomePhone         *NamedRef `xml:"home_phone,omitempty" json:"home_phone,omitempty"`
+	WorkPhone         *NamedRef `xml:"work_phone,omitempty" json:"work_phone,omitempty"`
+	MobilePhone       *NamedRef `xml:"mobile_phone,omitempty" json:"mobile_phone,omitempty"`
+	EmergencyPhone    *NamedRef `xml:"emergency_phone,omitempty" json:"emergency_phone,omitempty"`
+	Birthday          *NamedRef `xml:"birthday,omitempty" json:"birthday,omitempty"`
+	Anniversary       *NamedRef `xml:"anniversary,omitempty" json:"anniversary,omitempty"`
+	Gender            *NamedRef `xml:"gender,omitempty" json:"gender,omitempty"`
+	MaritalStatus     *NamedRef `xml:"marital_status,omitempty" json:"marital_status,omitempty"`
+	UserDefinedFields *NamedRef `xml:"user_defined_fields,omitempty" json:"user_defined_fields,omitempty"`
+	Allergies         *NamedRef `xml:"allergies,omitempty" json:"allergies,omitempty"`
+}
+
+// FamilyMember is another member of the family of an individual.
+type FamilyMember struct {
+	Individual     NamedRef `xml:"individual,omitempty" json:"individual,omitempty"`
+	FamilyPosition string   `xml:"family_position,omitempty" json:"family_position,omitempty"`
+}
+
+// CustomFields is the list of labels of user-defined fields in
+// custom_field_labels responses.
+type CustomFields struct {
+	Count       int            `xml:"count,attr,omitempty" json:"count,omitempty"`
+	CustomField []*CustomField `xml:"custom_field,omitempty" json:"custom_field,omitempty"`
+}
+
+// CustomField is a user-defined field of an individual, or its label in
+// custom_field_labels responses.
+type CustomField struct {
+	Name      string    `xml:"name,omitempty" json:"name,omitempty"`
+	Label     string    `xml:"label,omitempty" json:"label,omitempty"`
+	Text      string    `xml:"text,omitempty" json:"text,omitempty"`
+	Date      string    `xml:"date,omitempty" json:"date,omitempty"`
+	Selection *NamedRef `xml:"selection,omitempty" json:"selection,omitempty"`
+	AdminOnly string    `xml:"admin_only,omitempty" json:"admin_only,omitempty"`
+}
+
+// SignificantEvent is a significant event of an individual in
+// individual_significant_events responses.
+type SignificantEvent struct {
+	ID    string `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name  string `xml:"name,omitempty" json:"name,omitempty"`
+	Date  string `xml:"date,omitempty" json:"date,omitempty"`
+	Notes string `xml:"notes,omitempty" json:"notes,omitempty"`
+}
+
+// IndividualGroup is a group of an individual in individual_groups responses.
+type IndividualGroup struct {
+	ID         string   `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name       string   `xml:"name,omitempty" json:"name,omitempty"`
+	GroupType  NamedRef `xml:"group_type,omitempty" json:"group_type,omitempty"`
+	Department NamedRef `xml:"department,omitempty" json:"department,omitempty"`
+	Campus     NamedRef `xml:"campus,omitempty" json:"campus,omitempty"`
+	Status     NamedRef `xml:"status,omitempty" json:"status,omitempty"`
+	Joined     string   `xml:"date_joined,omitempty" json:"date_joined,omitempty"`
+}
+
+// Families is the list of families in family_detail responses.
+type Families struct {
+	Count  int       `xml:"count,attr,omitempty" json:"count,omitempty"`
+	Family []*Family `xml:"family,omitempty" json:"family,omitempty"`
+}
+
+// Family is a household, with the profiles of its members.
+type Family struct {
+	ID          string        `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Modified    string        `xml:"modified,omitempty" json:"modified,omitempty"`
+	Individuals []*Individual `xml:"individuals>individual,omitempty" json:"individuals,omitempty"`
+}
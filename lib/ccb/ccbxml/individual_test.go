@@ -0,0 +1,120 @@
+package ccbxml
+
+import "testing"
+
+func TestIndividual(t *testing.T) {
+	resp, _ := roundTrip(t, "individual_profile_from_id.xml")
+
+	individuals := resp.Response.Individuals
+	if individuals == nil || individuals.Count != 1 || len(individuals.Individual) != 1 {
+		t.Fatalf("individuals = %+v, want one", individuals)
+	}
+	v := individuals.Individual[0]
+	if v.ID != "51" || v.FirstName != "Jane" || v.Email != "Jane@Example.com" || v.Active != "true" {
+		t.Errorf("individual = %+v", v)
+	}
+	if v.Campus == nil || *v.Campus != (NamedRef{ID: "2", Name: "JDD"}) {
+		t.Errorf("campus = %+v", v.Campus)
+	}
+	if v.Family == nil || v.Family.ID != "9" {
+		t.Errorf("family = %+v", v.Family)
+	}
+	if len(v.FamilyMembers) != 2 || v.FamilyMembers[0].Individual != (NamedRef{ID: "52", Name: "John Doe"}) || v.FamilyMembers[1].FamilyPosition != "c" {
+		t.Errorf("family members = %+v", v.FamilyMembers)
+	}
+	if len(v.Addresses) != 1 || v.Addresses[0].Type != "mailing" || v.Addresses[0].City != "Austin" ||
+		v.Addresses[0].Country == nil || v.Addresses[0].Country.Code != "US" {
+		t.Errorf("addresses = %+v", v.Addresses)
+	}
+	if len(v.Phones) != 3 || *v.Phones[1] != (Phone{Type: "mobile", Number: "512.555.0101"}) {
+		t.Errorf("phones = %+v", v.Phones)
+	}
+	if v.MembershipType == nil || v.MembershipType.ID != "1" {
+		t.Errorf("membership type = %+v", v.MembershipType)
+	}
+	if len(v.UserDefinedTextFields) != 3 || v.UserDefinedTextFields[0].Label != "Serving team interest" {
+		t.Errorf("text fields = %+v", v.UserDefinedTextFields)
+	}
+	if len(v.UserDefinedDateFields) != 1 || v.UserDefinedDateFields[0].Date != "2019-09-01" {
+		t.Errorf("date fields = %+v", v.UserDefinedDateFields)
+	}
+	if len(v.UserDefinedPulldownFields) != 1 || v.UserDefinedPulldownFields[0].Selection == nil ||
+		*v.UserDefinedPulldownFields[0].Selection != (NamedRef{ID: "3", Name: "Friend"}) {
+		t.Errorf("pulldown fields = %+v", v.UserDefinedPulldownFields)
+	}
+}
+
+func TestIndividualSearch(t *testing.T) {
+	resp, _ := roundTrip(t, "individual_search.xml")
+
+	individuals := resp.Response.Individuals
+	if individuals == nil || individuals.Count != 2 || len(individuals.Individual) != 2 {
+		t.Fatalf("individuals = %+v, want two", individuals)
+	}
+	privacy := individuals.Individual[1].PrivacySettings
+	if privacy == nil || privacy.ProfileListed != "true" || privacy.MailingAddress == nil ||
+		*privacy.MailingAddress != (NamedRef{ID: "3", Name: "Everyone"}) {
+		t.Errorf("privacy settings = %+v", privacy)
+	}
+}
+
+func TestSignificantEvents(t *testing.T) {
+	resp, _ := roundTrip(t, "individual_significant_events.xml")
+
+	if resp.Response.Individuals == nil || len(resp.Response.Individuals.Individual) != 1 {
+		t.Fatalf("individuals = %+v, want one", resp.Response.Individuals)
+	}
+	events := resp.Response.Individuals.Individual[0].SignificantEvents
+	if len(events) != 3 {
+		t.Fatalf("significant events = %+v, want three", events)
+	}
+	want := SignificantEvent{ID: "2", Name: "Growth Track Step 1", Date: "2026-09-01", Notes: "Done"}
+	if *events[0] != want {
+		t.Errorf("significant event = %+v, want %+v", *events[0], want)
+	}
+}
+
+func TestIndividualGroups(t *testing.T) {
+	resp, _ := roundTrip(t, "individual_groups.xml")
+
+	if resp.Response.Individuals == nil || len(resp.Response.Individuals.Individual) != 1 {
+		t.Fatalf("individuals = %+v, want one", resp.Response.Individuals)
+	}
+	groups := resp.Response.Individuals.Individual[0].Groups
+	if len(groups) != 2 {
+		t.Fatalf("groups = %+v, want two", groups)
+	}
+	g := groups[1]
+	if g.ID != "9" || g.GroupType.Name != "Serve Team" || g.Status.Name != "Main Leader" || g.Joined != "2024-01-10" {
+		t.Errorf("group = %+v", g)
+	}
+}
+
+func TestCustomFieldLabels(t *testing.T) {
+	resp, _ := roundTrip(t, "custom_field_labels.xml")
+
+	fields := resp.Response.CustomFields
+	if fields == nil || fields.Count != 3 || len(fields.CustomField) != 4 {
+		t.Fatalf("custom fields = %+v, want four", fields)
+	}
+	want := CustomField{Name: "udf_ind_text_2", Label: "Allergy notes", AdminOnly: "true"}
+	if got := *fields.CustomField[1]; got != want {
+		t.Errorf("custom field = %+v, want %+v", got, want)
+	}
+}
+
+func TestFamily(t *testing.T) {
+	resp, _ := roundTrip(t, "family_detail.xml")
+
+	families := resp.Response.Families
+	if families == nil || len(families.Family) != 1 {
+		t.Fatalf("families = %+v, want one", families)
+	}
+	f := families.Family[0]
+	if f.ID != "30" || f.Modified != "2026-01-02 10:00:00" || len(f.Individuals) != 3 {
+		t.Fatalf("family = %+v", f)
+	}
+	if m := f.Individuals[1]; m.ID != "5" || m.FamilyPosition != "Primary Contact" || len(m.Phones) != 1 {
+		t.Errorf("member = %+v", m)
+	}
+}
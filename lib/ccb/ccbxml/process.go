@@ -0,0 +1,43 @@
+package ccbxml
+
+// Processes is the list of processes in process_list responses.
+type Processes struct {
+	Count   int        `xml:"count,attr,omitempty" json:"count,omitempty"`
+	Process []*Process `xml:"process,omitempty" json:"process,omitempty"`
+}
+
+// Process is a process, such as following up with first time guests.
+type Process struct {
+	ID          string   `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name        string   `xml:"name,omitempty" json:"name,omitempty"`
+	Description string   `xml:"description,omitempty" json:"description,omitempty"`
+	Campus      NamedRef `xml:"campus,omitempty" json:"campus,omitempty"`
+	Manager     NamedRef `xml:"manager,omitempty" json:"manager,omitempty"`
+}
+
+// Queues is the list of queues in queue_list and queue_individuals responses.
+type Queues struct {
+	Count int      `xml:"count,attr,omitempty" json:"count,omitempty"`
+	Queue []*Queue `xml:"queue,omitempty" json:"queue,omitempty"`
+}
+
+// Queue is a step of a process. queue_individuals also returns the individuals in
+// it.
+type Queue struct {
+	ID          string             `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name        string             `xml:"name,omitempty" json:"name,omitempty"`
+	Description string             `xml:"description,omitempty" json:"description,omitempty"`
+	Manager     NamedRef           `xml:"manager,omitempty" json:"manager,omitempty"`
+	Individuals []*QueueIndividual `xml:"individuals>individual,omitempty" json:"individuals,omitempty"`
+}
+
+// QueueIndividual is an individual in a queue.
+type QueueIndividual struct {
+	ID        string   `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name      string   `xml:"name,omitempty" json:"name,omitempty"`
+	Status    string   `xml:"status,omitempty" json:"status,omitempty"`
+	Manager   NamedRef `xml:"manager,omitempty" json:"manager,omitempty"`
+	Note      string   `xml:"note,omitempty" json:"note,omitempty"`
+	DueDate   string   `xml:"due_date,omitempty" json:"due_date,omitempty"`
+	DateAdded string   `xml:"date_added,omitempty" json:"date_added,omitempty"`
+}
@@ -0,0 +1,47 @@
+package ccbxml
+
+import "testing"
+
+func TestProcesses(t *testing.T) {
+	resp, _ := roundTrip(t, "process_list.xml")
+
+	processes := resp.Response.Processes
+	if processes == nil || len(processes.Process) != 1 {
+		t.Fatalf("processes = %+v, want one", processes)
+	}
+	want := Process{
+		ID:          "3",
+		Name:        "Guest follow-up",
+		Description: "New guests",
+		Campus:      NamedRef{ID: "2", Name: "Johnson Drive District"},
+		Manager:     NamedRef{ID: "9", Name: "Pat Pastor"},
+	}
+	if got := *processes.Process[0]; got != want {
+		t.Errorf("process = %+v, want %+v", got, want)
+	}
+}
+
+func TestQueueIndividuals(t *testing.T) {
+	resp, _ := roundTrip(t, "queue_individuals.xml")
+
+	queues := resp.Response.Queues
+	if queues == nil || len(queues.Queue) != 1 {
+		t.Fatalf("queues = %+v, want one", queues)
+	}
+	q := queues.Queue[0]
+	if q.ID != "12" || q.Name != "First-time guest" || len(q.Individuals) != 1 {
+		t.Fatalf("queue = %+v", q)
+	}
+	want := QueueIndividual{
+		ID:        "5",
+		Name:      "Jane Doe",
+		Status:    "in_progress",
+		Manager:   NamedRef{ID: "9", Name: "Pat Pastor"},
+		Note:      "Call",
+		DueDate:   "2026-10-25",
+		DateAdded: "2026-10-18 09:00:00",
+	}
+	if got := *q.Individuals[0]; got != want {
+		t.Errorf("queue individual = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+// Package ccbxml models the XML documents returned by the CCB API. Each CCB entity
+// has one named type, shared by every service returning it, so new services only
+// add the fields they need to it. The types decode and encode back to the same
+// document, and values are kept as the strings CCB sends; lib/ccb parses them.
+package ccbxml
+
+import "encoding/xml"
+
+// Response is the document returned by every CCB service. Only the element for
+// the records the service returns is set in Response.
+type Response struct {
+	XMLName  xml.Name `xml:"ccb_api" json:"-"`
+	Request  Request  `xml:"request,omitempty" json:"request,omitempty"`
+	Response Body     `xml:"response,omitempty" json:"response,omitempty"`
+}
+
+// Request echoes the parameters of the request.
+type Request struct {
+	Parameters struct {
+		Argument []Argument `xml:"argument,omitempty" json:"argument,omitempty"`
+	} `xml:"parameters,omitempty" json:"parameters,omitempty"`
+}
+
+// Argument is a parameter of the request.
+type Argument struct {
+	Value string `xml:"value,attr,omitempty" json:"value,omitempty"`
+	Name  string `xml:"name,attr,omitempty" json:"name,omitempty"`
+}
+
+// Body holds the records returned by the service.
+type Body struct {
+	Service                string                  `xml:"service,omitempty" json:"service,omitempty"`
+	ServiceAction          string                  `xml:"service_action,omitempty" json:"service_action,omitempty"`
+	Availability           string                  `xml:"availability,omitempty" json:"availability,omitempty"`
+	Individuals            *Individuals            `xml:"individuals,omitempty" json:"individuals,omitempty"`
+	Campuses               *Campuses               `xml:"campuses,omitempty" json:"campuses,omitempty"`
+	Groups                 *Groups                 `xml:"groups,omitempty" json:"groups,omitempty"`
+	Items                  *Items                  `xml:"items,omitempty" json:"items,omitempty"`
+	Batches                *Batches                `xml:"batches,omitempty" json:"batches,omitempty"`
+	TransactionDetailTypes *TransactionDetailTypes `xml:"transaction_detail_types,omitempty" json:"transaction_detail_types,omitempty"`
+	CustomFields           *CustomFields           `xml:"custom_fields,omitempty" json:"custom_fields,omitempty"`
+	Families               *Families               `xml:"families,omitempty" json:"families,omitempty"`
+	Processes              *Processes              `xml:"processes,omitempty" json:"processes,omitempty"`
+	Queues                 *Queues                 `xml:"queues,omitempty" json:"queues,omitempty"`
+	Events                 *Events                 `xml:"events,omitempty" json:"events,omitempty"`
+	FormResponses          *FormResponses          `xml:"form_responses,omitempty" json:"form_responses,omitempty"`
+	Errors                 *Errors                 `xml:"errors,omitempty" json:"errors,omitempty"`
+}
+
+// Errors are the errors returned in the body of a response, such as an invalid
+// parameter.
+type Errors struct {
+	Error []Error `xml:"error,omitempty" json:"errors,omitempty"`
+}
+
+// Error is an error returned by CCB.
+type Error struct {
+	Number  string `xml:"number,attr,omitempty" json:"number,omitempty"`
+	Type    string `xml:"type,attr,omitempty" json:"type,omitempty"`
+	Message string `xml:",chardata" json:"message,omitempty"`
+}
+
+// NamedRef is a reference to another record, with its name as the text, e.g.
+// <campus id="1">Brooklyn</campus>. Some references have no name.
+type NamedRef struct {
+	ID   string `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name string `xml:",chardata" json:"name,omitempty"`
+}
+
+// Phone is a phone number.
+type Phone struct {
+	Type   string `xml:"type,attr,omitempty" json:"type,omitempty"`
+	Number string `xml:",chardata" json:"number,omitempty"`
+}
+
+// Address is an address of an individual or group, or the location of an event.
+type Address struct {
+	Type          string   `xml:"type,attr,omitempty" json:"type,omitempty"`
+	Name          string   `xml:"name,omitempty" json:"name,omitempty"`
+	StreetAddress string   `xml:"street_address,omitempty" json:"street_address,omitempty"`
+	City          string   `xml:"city,omitempty" json:"city,omitempty"`
+	State         string   `xml:"state,omitempty" json:"state,omitempty"`
+	Zip           string   `xml:"zip,omitempty" json:"zip,omitempty"`
+	Country       *Country `xml:"country,omitempty" json:"country,omitempty"`
+	Line1         string   `xml:"line_1,omitempty" json:"line_1,omitempty"`
+	Line2         string   `xml:"line_2,omitempty" json:"line_2,omitempty"`
+	Latitude      string   `xml:"latitude,omitempty" json:"latitude,omitempty"`
+	Longitude     string   `xml:"longitude,omitempty" json:"longitude,omitempty"`
+}
+
+// Country is the country of an address.
+type Country struct {
+	Code string `xml:"code,attr,omitempty" json:"code,omitempty"`
+	Name string `xml:",chardata" json:"name,omitempty"`
+}
+
+// Campuses is the list of campuses in campus_list responses.
+type Campuses struct {
+	Campus []*Campus `xml:"campus,omitempty" json:"campus,omitempty"`
+}
+
+// Campus is a campus of the church.
+type Campus struct {
+	ID     string `xml:"id,attr,omitempty" json:"id,omitempty"`
+	Name   string `xml:"name,omitempty" json:"name,omitempty"`
+	Active string `xml:"active,omitempty" json:"active,omitempty"`
+}
+
+// Items is a list of items, returned by public_calendar_listing and lookup table
+// services such as significant_event_list.
+type Items struct {
+	Count int     `xml:"count,attr,omitempty" json:"count,omitempty"`
+	Item  []*Item `xml:"item,omitempty" json:"item,omitempty"`
+}
+
+// Item is an occurrence of an event in public_calendar_listing responses, or an
+// entry of a lookup table.
+type Item struct {
+	ID    string `xml:"id,omitempty" json:"id,omitempty"`
+	Name  string `xml:"name,omitempty" json:"name,omitempty"`
+	Order string `xml:"order,omitempty" json:"order,omitempty"`
+
+	EventID          string `xml:"event_id,omitempty" json:"event_id,omitempty"`
+	Date             string `xml:"date,omitempty" json:"date,omitempty"`
+	EventName        string `xml:"event_name,omitempty" json:"event_name,omitempty"`
+	EventDescription string `xml:"event_description,omitempty" json:"event_description,omitempty"`
+	StartTime        string `xml:"start_time,omitempty" json:"start_time,omitempty"`
+	EndTime          string `xml:"end_time,omitempty" json:"end_time,omitempty"`
+	EventDuration    string `xml:"event_duration,omitempty" json:"event_duration,omitempty"`
+	EventType        string `xml:"event_type,omitempty" json:"event_type,omitempty"`
+	Location         string `xml:"location,omitempty" json:"location,omitempty"`
+	GroupName        string `xml:"group_name,omitempty" json:"group_name,omitempty"`
+	GroupType        string `xml:"group_type,omitempty" json:"group_type,omitempty"`
+	GroupingName     string `xml:"grouping_name,omitempty" json:"grouping_name,omitempty"`
+	LeaderName       string `xml:"leader_name,omitempty" json:"leader_name,omitempty"`
+}
@@ -0,0 +1,99 @@
+package ccbxml
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// roundTrip decodes the fixture testdata/name, encodes it back and decodes the
+// result, failing the test unless both decode to the same Response. Returns the
+// decoded fixture and its encoding.
+func roundTrip(t *testing.T, name string) (*Response, []byte) {
+	t.Helper()
+	fixture, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Response
+	if err := xml.Unmarshal(fixture, &decoded); err != nil {
+		t.Fatalf("decode %s: %v", name, err)
+	}
+	encoded, err := xml.Marshal(&decoded)
+	if err != nil {
+		t.Fatalf("encode %s: %v", name, err)
+	}
+	var again Response
+	if err := xml.Unmarshal(encoded, &again); err != nil {
+		t.Fatalf("decode encoded %s: %v\n%s", name, err, encoded)
+	}
+	if !reflect.DeepEqual(decoded, again) {
+		t.Errorf("%s changed after encoding:\n%s", name, encoded)
+	}
+	return &decoded, encoded
+}
+
+func TestResponse(t *testing.T) {
+	resp, _ := roundTrip(t, "individual_search.xml")
+
+	if got := resp.Response.Service; got != "individual_search" {
+		t.Errorf("service = %q, want individual_search", got)
+	}
+	args := resp.Request.Parameters.Argument
+	if len(args) != 3 || args[0] != (Argument{Name: "srv", Value: "individual_search"}) {
+		t.Errorf("arguments = %+v", args)
+	}
+	if resp.Response.Errors != nil {
+		t.Errorf("errors = %+v, want none", resp.Response.Errors)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	resp, _ := roundTrip(t, "error.xml")
+
+	if resp.Response.Errors == nil || len(resp.Response.Errors.Error) != 1 {
+		t.Fatalf("errors = %+v, want one", resp.Response.Errors)
+	}
+	want := Error{Number: "002", Type: "Missing Argument", Message: "The individual_id argument is required."}
+	if got := resp.Response.Errors.Error[0]; got != want {
+		t.Errorf("error = %+v, want %+v", got, want)
+	}
+}
+
+func TestCampuses(t *testing.T) {
+	resp, _ := roundTrip(t, "campus_list.xml")
+
+	campuses := resp.Response.Campuses
+	if campuses == nil || len(campuses.Campus) != 2 {
+		t.Fatalf("campuses = %+v, want two", campuses)
+	}
+	want := Campus{ID: "2", Name: "Johnson Drive District", Active: "true"}
+	if got := *campuses.Campus[1]; got != want {
+		t.Errorf("campus = %+v, want %+v", got, want)
+	}
+}
+
+func TestItems(t *testing.T) {
+	resp, _ := roundTrip(t, "significant_event_list.xml")
+	items := resp.Response.Items
+	if items == nil || items.Count != 3 || len(items.Item) != 3 {
+		t.Fatalf("items = %+v, want three", items)
+	}
+	if got := *items.Item[0]; got.ID != "2" || got.Name != "Growth Track Step 1" || got.Order != "2" {
+		t.Errorf("item = %+v", got)
+	}
+
+	resp, _ = roundTrip(t, "public_calendar_listing.xml")
+	items = resp.Response.Items
+	if items == nil || len(items.Item) != 4 {
+		t.Fatalf("items = %+v, want four", items)
+	}
+	first := items.Item[0]
+	if first.Date != "2026-10-25" || first.EventName != "Sunday Service" || first.StartTime != "10:00:00" ||
+		first.EventDuration != "90" || first.GroupName != "Church" || first.LeaderName != "Pastor" {
+		t.Errorf("occurrence = %+v", first)
+	}
+}
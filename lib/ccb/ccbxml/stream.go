@@ -0,0 +1,326 @@
+package ccbxml
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Stream decodes a response from r a token at a time, calling fn with each record
+// element at path in the body, separated by slashes, e.g. "groups/group" for the
+// groups of a list, or "groups/group/participants/participant" for the
+// participants of each group. fn must consume the record, normally with
+// d.DecodeElement, and the record is not kept, so memory is bounded by the largest
+// record rather than the page. Stops at the first error from fn and returns it.
+//
+// The returned Response has the request, service and errors of the document. The
+// list at the start of path is set but left empty if it is in the document, so a
+// missing list can be told from an empty one. Any other elements of the body are
+// left unset.
+func Stream(r io.Reader, path string, fn func(d *xml.Decoder, start *xml.StartElement) error) (*Response, error) {
+	list, records := splitPath(path)
+	d := xml.NewDecoder(r)
+	var resp Response
+	var open []string // Names of the open elements.
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			if len(open) == 0 && resp.XMLName.Local != "" {
+				return &resp, nil
+			}
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch depth, name := len(open), t.Name.Local; {
+			case depth == 0:
+				if name != "ccb_api" {
+					return nil, errors.New("unexpected root element " + name)
+				}
+				resp.XMLName = t.Name
+				open = append(open, name)
+			case depth == 1 && name == "request":
+				err = d.DecodeElement(&resp.Request, &t)
+			case depth == 1 && name == "response":
+				open = append(open, name)
+			case depth == 2 && name == list:
+				setEmptyList(&resp.Response, name)
+				err = StreamElement(d, records, fn)
+			case depth == 2:
+				err = decodeBodyElement(d, &t, &resp.Response)
+			default:
+				err = d.Skip()
+			}
+		case xml.EndElement:
+			open = open[:len(open)-1]
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// StreamElement streams the records at path within the element whose start was
+// just read from d, calling fn with each like Stream, e.g. path
+// "participants/participant" within a group. It returns once the end of the
+// element has been read.
+func StreamElement(d *xml.Decoder, path string, fn func(d *xml.Decoder, start *xml.StartElement) error) error {
+	name, rest := splitPath(path)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Local != name:
+				err = d.Skip()
+			case rest == "":
+				err = fn(d, &t)
+			default:
+				err = StreamElement(d, rest, fn)
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// splitPath splits the first element name from a record path.
+func splitPath(path string) (name, rest string) {
+	if i := strings.Index(path, "/"); i >= 0 {
+		return path[:i], path[i+1:]
+	}
+	return path, ""
+}
+
+// setEmptyList sets the list of b with the element name to an empty list.
+func setEmptyList(b *Body, name string) {
+	switch name {
+	case "individuals":
+		b.Individuals = &Individuals{}
+	case "campuses":
+		b.Campuses = &Campuses{}
+	case "groups":
+		b.Groups = &Groups{}
+	case "items":
+		b.Items = &Items{}
+	case "batches":
+		b.Batches = &Batches{}
+	case "transaction_detail_types":
+		b.TransactionDetailTypes = &TransactionDetailTypes{}
+	case "custom_fields":
+		b.CustomFields = &CustomFields{}
+	case "families":
+		b.Families = &Families{}
+	case "processes":
+		b.Processes = &Processes{}
+	case "queues":
+		b.Queues = &Queues{}
+	case "events":
+		b.Events = &Events{}
+	case "form_responses":
+		b.FormResponses = &FormResponses{}
+	}
+}
+
+// decodeBodyElement decodes the element of the response body other than the list
+// being streamed into b, skipping elements which aren't needed without the list.
+func decodeBodyElement(d *xml.Decoder, start *xml.StartElement, b *Body) error {
+	switch start.Name.Local {
+	case "service":
+		return d.DecodeElement(&b.Service, start)
+	case "service_action":
+		return d.DecodeElement(&b.ServiceAction, start)
+	case "availability":
+		return d.DecodeElement(&b.Availability, start)
+	case "errors":
+		b.Errors = &Errors{}
+		return d.DecodeElement(b.Errors, start)
+	}
+	return d.Skip()
+}
+
+// EachIndividual streams the individuals of individual_profiles and
+// individual_search responses to fn.
+func EachIndividual(r io.Reader, fn func(*Individual) error) (*Response, error) {
+	return Stream(r, "individuals/individual", func(d *xml.Decoder, start *xml.StartElement) error {
+		var v Individual
+		if err := d.DecodeElement(&v, start); err != nil {
+			return err
+		}
+		return fn(&v)
+	})
+}
+
+// EachCampus streams the campuses of campus_list responses to fn.
+func EachCampus(r io.Reader, fn func(*Campus) error) (*Response, error) {
+	return Stream(r, "campuses/campus", func(d *xml.Decoder, start *xml.StartElement) error {
+		var v Campus
+		if err := d.DecodeElement(&v, start); err != nil {
+			return err
+		}
+		return fn(&v)
+	})
+}
+
+// EachGroup streams the groups of group_profiles responses to fn.
+func EachGroup(r io.Reader, fn func(*Group) error) (*Response, error) {
+	return Stream(r, "groups/group", func(d *xml.Decoder, start *xml.StartElement) error {
+		var v Group
+		if err := d.DecodeElement(&v, start); err != nil {
+			return err
+		}
+		return fn(&v)
+	})
+}
+
+// EachItem streams the items of public_calendar_listing and lookup table responses
+// to fn.
+func EachItem(r io.Reader, fn func(*Item) error) (*Response, error) {
+	return Stream(r, "items/item", func(d *xml.Decoder, start *xml.StartElement) error {
+		var v Item
+		if err := d.DecodeElement(&v, start); err != nil {
+			return err
+		}
+		return fn(&v)
+	})
+}
+
+// EachBatch streams the batches of batch_profiles_in_date_range responses to fn.
+func EachBatch(r io.Reader, fn func(*Batch) error) (*Response, error) {
+	return Stream(r, "batches/batch", func(d *xml.Decoder, start *xml.StartElement) error {
+		var v Batch
+		if err := d.DecodeElement(&v, start); err != nil {
+			return err
+		}
+		return fn(&v)
+	})
+}
+
+// EachTransactionDetailType streams the types of transaction_detail_type_list
+// responses to fn.
+func EachTransactionDetailType(r io.Reader, fn func(*TransactionDetailType) error) (*Response, error) {
+	return Stream(r, "transaction_detail_types/transaction_detail_type", func(d *xml.Decoder, start *xml.StartElement) error {
+		var v TransactionDetailType
+		if err := d.DecodeElement(&v, start); err != nil {
+			return err
+		}
+		return fn(&v)
+	})
+}
+
+// EachCustomField streams the labels of custom_field_labels responses to fn.
+func EachCustomField(r io.Reader, fn func(*CustomField) error) (*Response, error) {
+	return Stream(r, "custom_fields/custom_field", func(d *xml.Decoder, start *xml.StartElement) error {
+		var v CustomField
+		if err := d.DecodeElement(&v, start); err != nil {
+			return err
+		}
+		return fn(&v)
+	})
+}
+
+// EachProcess streams the processes of process_list responses to fn.
+func EachProcess(r io.Reader, fn func(*Process) error) (*Response, error) {
+	return Stream(r, "processes/process", func(d *xml.Decoder, start *xml.StartElement) error {
+		var v Process
+		if err := d.DecodeElement(&v, start); err != nil {
+			return err
+		}
+		return fn(&v)
+	})
+}
+
+// EachEvent streams the events of attendance_profiles and event_profiles responses
+// to fn.
+func EachEvent(r io.Reader, fn func(*Event) error) (*Response, error) {
+	return Stream(r, "events/event", func(d *xml.Decoder, start *xml.StartElement) error {
+		var v Event
+		if err := d.DecodeElement(&v, start); err != nil {
+			return err
+		}
+		return fn(&v)
+	})
+}
+
+// EachFormResponse streams the responses of form_responses responses to fn.
+func EachFormResponse(r io.Reader, fn func(*FormResponse) error) (*Response, error) {
+	return Stream(r, "form_responses/form_response", func(d *xml.Decoder, start *xml.StartElement) error {
+		var v FormResponse
+		if err := d.DecodeElement(&v, start); err != nil {
+			return err
+		}
+		return fn(&v)
+	})
+}
+
+// EachQueue streams the queues of queue_list responses to fn.
+func EachQueue(r io.Reader, fn func(*Queue) error) (*Response, error) {
+	return Stream(r, "queues/queue", func(d *xml.Decoder, start *xml.StartElement) error {
+		var v Queue
+		if err := d.DecodeElement(&v, start); err != nil {
+			return err
+		}
+		return fn(&v)
+	})
+}
+
+// EachQueueIndividual streams the individuals of queue_individuals responses to fn.
+// queue is called with the id of each queue before its individuals, so a queue with
+// no individuals can be told from a missing one.
+func EachQueueIndividual(r io.Reader, queue func(id string) error, fn func(*QueueIndividual) error) (*Response, error) {
+	return Stream(r, "queues/queue", func(d *xml.Decoder, start *xml.StartElement) error {
+		if err := queue(attr(start, "id")); err != nil {
+			return err
+		}
+		return StreamElement(d, "individuals/individual", func(d *xml.Decoder, start *xml.StartElement) error {
+			var v QueueIndividual
+			if err := d.DecodeElement(&v, start); err != nil {
+				return err
+			}
+			return fn(&v)
+		})
+	})
+}
+
+// EachGroupParticipant streams the participants of group_participants responses to
+// fn. group is called with the id of each group before its participants, so a
+// group with no participants can be told from a missing one.
+func EachGroupParticipant(r io.Reader, group func(id string) error, fn func(*Participant) error) (*Response, error) {
+	return Stream(r, "groups/group", func(d *xml.Decoder, start *xml.StartElement) error {
+		if err := group(attr(start, "id")); err != nil {
+			return err
+		}
+		return StreamElement(d, "participants/participant", func(d *xml.Decoder, start *xml.StartElement) error {
+			var v Participant
+			if err := d.DecodeElement(&v, start); err != nil {
+				return err
+			}
+			return fn(&v)
+		})
+	})
+}
+
+// attr returns the value of the attribute of the element, or "" if it has none.
+func attr(start *xml.StartElement, name string) string {
+	for _, a := range start.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,287 @@
+package ccbxml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fixture returns the contents of testdata/name and its full decode.
+func fixture(t *testing.T, name string) ([]byte, *Response) {
+	t.Helper()
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp Response
+	if err := xml.Unmarshal(b, &resp); err != nil {
+		t.Fatalf("decode %s: %v", name, err)
+	}
+	return b, &resp
+}
+
+func TestStreamRecords(t *testing.T) {
+	tests := []struct {
+		fixture string
+		stream  func(b []byte) (interface{}, error)
+		want    func(resp *Response) interface{}
+	}{
+		{
+			fixture: "individual_search.xml",
+			stream: func(b []byte) (interface{}, error) {
+				var got []*Individual
+				_, err := EachIndividual(bytes.NewReader(b), func(v *Individual) error {
+					got = append(got, v)
+					return nil
+				})
+				return got, err
+			},
+			want: func(resp *Response) interface{} { return resp.Response.Individuals.Individual },
+		},
+		{
+			fixture: "campus_list.xml",
+			stream: func(b []byte) (interface{}, error) {
+				var got []*Campus
+				_, err := EachCampus(bytes.NewReader(b), func(v *Campus) error {
+					got = append(got, v)
+					return nil
+				})
+				return got, err
+			},
+			want: func(resp *Response) interface{} { return resp.Response.Campuses.Campus },
+		},
+		{
+			fixture: "group_profiles.xml",
+			stream: func(b []byte) (interface{}, error) {
+				var got []*Group
+				_, err := EachGroup(bytes.NewReader(b), func(v *Group) error {
+					got = append(got, v)
+					return nil
+				})
+				return got, err
+			},
+			want: func(resp *Response) interface{} { return resp.Response.Groups.Group },
+		},
+		{
+			fixture: "public_calendar_listing.xml",
+			stream: func(b []byte) (interface{}, error) {
+				var got []*Item
+				_, err := EachItem(bytes.NewReader(b), func(v *Item) error {
+					got = append(got, v)
+					return nil
+				})
+				return got, err
+			},
+			want: func(resp *Response) interface{} { return resp.Response.Items.Item },
+		},
+		{
+			fixture: "batch_profiles_in_date_range.xml",
+			stream: func(b []byte) (interface{}, error) {
+				var got []*Batch
+				_, err := EachBatch(bytes.NewReader(b), func(v *Batch) error {
+					got = append(got, v)
+					return nil
+				})
+				return got, err
+			},
+			want: func(resp *Response) interface{} { return resp.Response.Batches.Batch },
+		},
+		{
+			fixture: "transaction_detail_type_list.xml",
+			stream: func(b []byte) (interface{}, error) {
+				var got []*TransactionDetailType
+				_, err := EachTransactionDetailType(bytes.NewReader(b), func(v *TransactionDetailType) error {
+					got = append(got, v)
+					return nil
+				})
+				return got, err
+			},
+			want: func(resp *Response) interface{} { return resp.Response.TransactionDetailTypes.TransactionDetailType },
+		},
+		{
+			fixture: "custom_field_labels.xml",
+			stream: func(b []byte) (interface{}, error) {
+				var got []*CustomField
+				_, err := EachCustomField(bytes.NewReader(b), func(v *CustomField) error {
+					got = append(got, v)
+					return nil
+				})
+				return got, err
+			},
+			want: func(resp *Response) interface{} { return resp.Response.CustomFields.CustomField },
+		},
+		{
+			fixture: "process_list.xml",
+			stream: func(b []byte) (interface{}, error) {
+				var got []*Process
+				_, err := EachProcess(bytes.NewReader(b), func(v *Process) error {
+					got = append(got, v)
+					return nil
+				})
+				return got, err
+			},
+			want: func(resp *Response) interface{} { return resp.Response.Processes.Process },
+		},
+		{
+			fixture: "attendance_profiles.xml",
+			stream: func(b []byte) (interface{}, error) {
+				var got []*Event
+				_, err := EachEvent(bytes.NewReader(b), func(v *Event) error {
+					got = append(got, v)
+					return nil
+				})
+				return got, err
+			},
+			want: func(resp *Response) interface{} { return resp.Response.Events.Event },
+		},
+		{
+			fixture: "form_responses.xml",
+			stream: func(b []byte) (interface{}, error) {
+				var got []*FormResponse
+				_, err := EachFormResponse(bytes.NewReader(b), func(v *FormResponse) error {
+					got = append(got, v)
+					return nil
+				})
+				return got, err
+			},
+			want: func(resp *Response) interface{} { return resp.Response.FormResponses.FormResponse },
+		},
+		{
+			fixture: "queue_list.xml",
+			stream: func(b []byte) (interface{}, error) {
+				var got []*Queue
+				_, err := EachQueue(bytes.NewReader(b), func(v *Queue) error {
+					got = append(got, v)
+					return nil
+				})
+				return got, err
+			},
+			want: func(resp *Response) interface{} { return resp.Response.Queues.Queue },
+		},
+		{
+			fixture: "queue_individuals.xml",
+			stream: func(b []byte) (interface{}, error) {
+				var got []*QueueIndividual
+				_, err := EachQueueIndividual(bytes.NewReader(b), func(string) error { return nil }, func(v *QueueIndividual) error {
+					got = append(got, v)
+					return nil
+				})
+				return got, err
+			},
+			want: func(resp *Response) interface{} { return resp.Response.Queues.Queue[0].Individuals },
+		},
+		{
+			fixture: "group_participants.xml",
+			stream: func(b []byte) (interface{}, error) {
+				var got []*Participant
+				_, err := EachGroupParticipant(bytes.NewReader(b), func(string) error { return nil }, func(v *Participant) error {
+					got = append(got, v)
+					return nil
+				})
+				return got, err
+			},
+			want: func(resp *Response) interface{} { return resp.Response.Groups.Group[0].Participants },
+		},
+	}
+	for _, tt := range tests {
+		b, resp := fixture(t, tt.fixture)
+		got, err := tt.stream(b)
+		if err != nil {
+			t.Errorf("%s: stream: %v", tt.fixture, err)
+			continue
+		}
+		if want := tt.want(resp); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: streamed %+v, want %+v", tt.fixture, got, want)
+		}
+	}
+}
+
+func TestStreamResponse(t *testing.T) {
+	b, want := fixture(t, "individual_search.xml")
+
+	resp, err := EachIndividual(bytes.NewReader(b), func(*Individual) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Response.Service != want.Response.Service {
+		t.Errorf("service = %q, want %q", resp.Response.Service, want.Response.Service)
+	}
+	if !reflect.DeepEqual(resp.Request, want.Request) {
+		t.Errorf("request = %+v, want %+v", resp.Request, want.Request)
+	}
+	if resp.Response.Individuals == nil || len(resp.Response.Individuals.Individual) != 0 {
+		t.Errorf("individuals = %+v, want an empty list", resp.Response.Individuals)
+	}
+	if resp.Response.Groups != nil {
+		t.Errorf("groups = %+v, want no list", resp.Response.Groups)
+	}
+}
+
+func TestStreamNestedParents(t *testing.T) {
+	doc := `<ccb_api><response><groups count="2">` +
+		`<group id="10"><name>Empty</name><participants count="0"></participants></group>` +
+		`<group id="11"><participants count="1"><participant id="50"><name>Jane</name></participant></participants></group>` +
+		`</groups></response></ccb_api>`
+
+	var got []string
+	_, err := EachGroupParticipant(bytes.NewReader([]byte(doc)), func(id string) error {
+		got = append(got, "group "+id)
+		return nil
+	}, func(v *Participant) error {
+		got = append(got, "participant "+v.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"group 10", "group 11", "participant 50"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("streamed %q, want %q", got, want)
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	b, want := fixture(t, "error.xml")
+
+	resp, err := EachIndividual(bytes.NewReader(b), func(*Individual) error {
+		t.Error("called with no individuals")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(resp.Response.Errors, want.Response.Errors) {
+		t.Errorf("errors = %+v, want %+v", resp.Response.Errors, want.Response.Errors)
+	}
+}
+
+func TestStreamStop(t *testing.T) {
+	b, _ := fixture(t, "group_profiles.xml")
+
+	stop := errors.New("stop")
+	var calls int
+	_, err := EachGroup(bytes.NewReader(b), func(*Group) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("err = %v after %d calls, want stop after 1", err, calls)
+	}
+}
+
+func TestStreamMalformed(t *testing.T) {
+	b, _ := fixture(t, "group_profiles.xml")
+
+	for _, doc := range [][]byte{
+		b[:len(b)/2],
+		[]byte(`<html><body>Maintenance</body></html>`),
+		nil,
+	} {
+		if _, err := EachGroup(bytes.NewReader(doc), func(*Group) error { return nil }); err == nil {
+			t.Errorf("no error streaming %q", doc)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package ccb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ContactFields maps contact details to the names of the CCB profile fields which
+// may hold them. The first non-empty field wins. Names are matched case insensitively.
+type ContactFields struct {
+	FirstName      []string `envconfig:"CCB_FIELD_FIRST_NAME"      default:"First Name,Name First"`
+	LastName       []string `envconfig:"CCB_FIELD_LAST_NAME"       default:"Last Name,Name Last"`
+	Email          []string `envconfig:"CCB_FIELD_EMAIL"           default:"Email,Email Address"`
+	MobilePhone    []string `envconfig:"CCB_FIELD_MOBILE_PHONE"    default:"Mobile Phone,Cell Phone"`
+	HomePhone      []string `envconfig:"CCB_FIELD_HOME_PHONE"      default:"Home Phone,Phone"`
+	WorkPhone      []string `envconfig:"CCB_FIELD_WORK_PHONE"      default:"Work Phone"`
+	Street         []string `envconfig:"CCB_FIELD_STREET"          default:"Street Address,Street"`
+	City           []string `envconfig:"CCB_FIELD_CITY"            default:"City"`
+	State          []string `envconfig:"CCB_FIELD_STATE"           default:"State"`
+	Zip            []string `envconfig:"CCB_FIELD_ZIP"             default:"Zip,Zip Code,Postal Code"`
+	MailingAddress []string `envconfig:"CCB_FIELD_MAILING_ADDRESS" default:"Mailing Address,Address"` // Whole address in one field.
+	Birthday       []string `envconfig:"CCB_FIELD_BIRTHDAY"        default:"Birthday,Date of Birth"`
+
+	DefaultCountryCode string `envconfig:"CCB_DEFAULT_COUNTRY_CODE" default:"1"` // Calling code for numbers without one.
+}
+
+// Contact holds contact details normalized from the profile fields of a form response.
+type Contact struct {
+	FirstName      string   `json:"first_name,omitempty"`
+	LastName       string   `json:"last_name,omitempty"`
+	Email          string   `json:"email,omitempty"`  // Lower-cased and validated.
+	Phones         []Phone  `json:"phones,omitempty"` // E.164, e.g. +15125550100.
+	MailingAddress *Address `json:"mailing_address,omitempty"`
+	Birthday       *Date    `json:"birthday,omitempty"`
+}
+
+// Date is a calendar date without a time, serialized as YYYY-MM-DD.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// dateLayout is the layout used to serialize dates.
+const dateLayout = "2006-01-02"
+
+// birthdayLayouts are the layouts birthdays are accepted in.
+var birthdayLayouts = []string{dateLayout, "01/02/2006", "1/2/2006", "January 2, 2006", "Jan 2, 2006"}
+
+// String returns the date as YYYY-MM-DD.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, int(d.Month), d.Day)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return err
+	}
+	*d = Date{Year: t.Year(), Month: t.Month(), Day: t.Day()}
+	return nil
+}
+
+// NormalizeContact builds the contact details from profile fields using the field
+// mapping. Values which cannot be normalized are left out and reported as warnings.
+func NormalizeContact(profile map[string]string, fields ContactFields) (*Contact, []string) {
+	var warnings []string
+	get := func(names []string) string {
+		for _, name := range names {
+			if v, ok := lookupFold(profile, name); ok && strings.TrimSpace(v) != "" {
+				return strings.TrimSpace(v)
+			}
+		}
+		return ""
+	}
+
+	c := &Contact{
+		FirstName: get(fields.FirstName),
+		LastName:  get(fields.LastName),
+	}
+
+	if raw := get(fields.Email); raw != "" {
+		if email, ok := NormalizeEmail(raw); ok {
+			c.Email = email
+		} else {
+			warnings = append(warnings, fmt.Sprintf("invalid email %q", raw))
+		}
+	}
+
+	phones := []struct {
+		typ   string
+		names []string
+	}{
+		{"mobile", fields.MobilePhone},
+		{"home", fields.HomePhone},
+		{"work", fields.WorkPhone},
+	}
+	for _, p := range phones {
+		raw := get(p.names)
+		if raw == "" {
+			continue
+		}
+		if number, ok := NormalizePhone(raw, fields.DefaultCountryCode); ok {
+			c.Phones = append(c.Phones, Phone{Type: p.typ, Number: number})
+		} else {
+			warnings = append(warnings, fmt.Sprintf("invalid %s phone %q", p.typ, raw))
+		}
+	}
+
+	addr := Address{
+		Type:          "mailing",
+		StreetAddress: get(fields.Street),
+		City:          get(fields.City),
+		State:         strings.ToUpper(get(fields.State)),
+		Zip:           get(fields.Zip),
+	}
+	if addr.StreetAddress == "" && addr.City == "" {
+		if raw := get(fields.MailingAddress); raw != "" {
+			addr = parseAddress(raw)
+		}
+	}
+	if addr.StreetAddress != "" || addr.City != "" || addr.Zip != "" {
+		c.MailingAddress = &addr
+	}
+
+	if raw := get(fields.Birthday); raw != "" {
+		if birthday, ok := parseDate(raw, birthdayLayouts); ok {
+			c.Birthday = &birthday
+		} else {
+			warnings = append(warnings, fmt.Sprintf("invalid birthday %q", raw))
+		}
+	}
+
+	return c, warnings
+}
+
+// NormalizeEmail lower-cases the email and checks it is a bare address.
+func NormalizeEmail(raw string) (string, bool) {
+	email := strings.ToLower(strings.TrimSpace(raw))
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || !strings.Contains(email[strings.LastIndex(email, "@"):], ".") {
+		return "", false
+	}
+	return email, true
+}
+
+var (
+	nonDigits      = regexp.MustCompile(`[^0-9]`)
+	phoneExtension = regexp.MustCompile(`(?i)\s*(x|ext\.?|extension)\s*\d+\s*$`) // e.g. "x123" or "ext. 123".
+)
+
+// NormalizePhone formats the number in E.164. Numbers without a leading + are
+// assumed to be national numbers for the default country code, which for North
+// America may also be written with a leading 1.
+func NormalizePhone(raw, defaultCountryCode string) (string, bool) {
+	raw = phoneExtension.ReplaceAllString(raw, "")
+	digits := nonDigits.ReplaceAllString(raw, "")
+	if strings.HasPrefix(strings.TrimSpace(raw), "+") {
+		if len(digits) < 8 || len(digits) > 15 {
+			return "", false
+		}
+		return "+" + digits, true
+	}
+
+	if defaultCountryCode == "1" {
+		if len(digits) == 11 && digits[0] == '1' {
+			digits = digits[1:]
+		}
+		if len(digits) != 10 {
+			return "", false
+		}
+		return "+1" + digits, true
+	}
+
+	digits = strings.TrimPrefix(digits, "0") // Trunk prefix.
+	if len(digits) < 6 || len(defaultCountryCode)+len(digits) > 15 {
+		return "", false
+	}
+	return "+" + defaultCountryCode + digits, true
+}
+
+// cityStateZip matches the last line of a US address, e.g. "Austin, TX 78701".
+var cityStateZip = regexp.MustCompile(`^(.+?),?\s+([A-Za-z]{2})\.?\s+(\d{5}(?:-\d{4})?)$`)
+
+// parseAddress splits an address written in a single field into its parts, on a
+// best effort basis. Anything that cannot be split is left in StreetAddress.
+func parseAddress(raw string) Address {
+	var lines []string
+	for _, l := range strings.FieldsFunc(raw, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	addr := Address{Type: "mailing"}
+
+	// Single line addresses are usually "street, city, ST zip".
+	if len(lines) == 1 {
+		if i := strings.Index(lines[0], ","); i > 0 && cityStateZip.MatchString(strings.TrimSpace(lines[0][i+1:])) {
+			lines = []string{strings.TrimSpace(lines[0][:i]), strings.TrimSpace(lines[0][i+1:])}
+		}
+	}
+
+	if n := len(lines); n > 1 {
+		if m := cityStateZip.FindStringSubmatch(lines[n-1]); m != nil {
+			addr.City, addr.State, addr.Zip = m[1], strings.ToUpper(m[2]), m[3]
+			lines = lines[:n-1]
+		}
+	}
+	addr.StreetAddress = strings.Join(lines, "\n")
+	return addr
+}
+
+// parseDate parses the value with the first matching layout.
+func parseDate(raw string, layouts []string) (Date, bool) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return Date{Year: t.Year(), Month: t.Month(), Day: t.Day()}, true
+		}
+	}
+	return Date{}, false
+}
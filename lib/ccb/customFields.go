@@ -0,0 +1,172 @@
+package ccb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb/ccbxml"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+)
+
+// CustomFieldType is the type of a user-defined field.
+type CustomFieldType string
+
+// The types of user-defined fields.
+const (
+	CustomFieldText     CustomFieldType = "text"
+	CustomFieldDate     CustomFieldType = "date"
+	CustomFieldPulldown CustomFieldType = "pulldown"
+)
+
+// customFieldLabelsCacheKey is the key the custom field labels are cached under.
+const customFieldLabelsCacheKey = "custom_field_labels"
+
+// CustomFieldLabel is the label of a user-defined field, which is what the church
+// calls the field, e.g. "How did you hear about us".
+type CustomFieldLabel struct {
+	Name      string          `json:"name"` // e.g. udf_ind_pulldown_1.
+	Label     string          `json:"label"`
+	Type      CustomFieldType `json:"type,omitempty"`
+	AdminOnly bool            `json:"admin_only"`
+}
+
+// CustomFieldOption is an option of a pulldown field.
+type CustomFieldOption struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// CustomField is the value of a user-defined field of an individual. Only the
+// value for its type is set.
+type CustomField struct {
+	Name   string             `json:"name"` // e.g. udf_pulldown_1.
+	Type   CustomFieldType    `json:"type"`
+	Text   string             `json:"text,omitempty"`
+	Date   *time.Time         `json:"date,omitempty"`
+	Option *CustomFieldOption `json:"option,omitempty"`
+}
+
+// GetCustomFieldLabels returns the labels of the user-defined fields. They rarely
+// change, so are cached for CustomFieldLabelsTTL.
+func (svc *defaultService) GetCustomFieldLabels(ctx context.Context) ([]CustomFieldLabel, error) {
+	if cached, ok := svc.labels.Get(customFieldLabelsCacheKey); ok {
+		return cached.([]CustomFieldLabel), nil
+	}
+	vouslog.GetLogger(ctx).Info("Getting custom field labels from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "custom_field_labels")
+
+	labels := []CustomFieldLabel{}
+	if err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		return ccbxml.EachCustomField(r, func(v *ccbxml.CustomField) error {
+			if v.Name == "" || strings.TrimSpace(v.Label) == "" {
+				return nil // Unused fields have no label.
+			}
+			labels = append(labels, CustomFieldLabel{
+				Name:      v.Name,
+				Label:     strings.TrimSpace(v.Label),
+				Type:      customFieldType(v.Name),
+				AdminOnly: v.AdminOnly == "true",
+			})
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	svc.labels.Set(customFieldLabelsCacheKey, labels)
+	return labels, nil
+}
+
+// customFieldType returns the type of the field from its name.
+func customFieldType(name string) CustomFieldType {
+	for _, t := range []CustomFieldType{CustomFieldText, CustomFieldDate, CustomFieldPulldown} {
+		if strings.Contains(name, "_"+string(t)+"_") {
+			return t
+		}
+	}
+	return ""
+}
+
+// customFieldKey identifies a field of individuals across the names used in
+// profiles (udf_text_1) and in custom_field_labels (udf_ind_text_1).
+func customFieldKey(name string) string {
+	return strings.Replace(name, "udf_ind_", "udf_", 1)
+}
+
+// customFieldsFromCCB parses the user-defined fields of an individual, keyed by
+// label, or by name for fields without a label in the profile. Empty fields are
+// left out.
+func customFieldsFromCCB(v *ccbxml.Individual, loc *time.Location) (map[string]CustomField, []string) {
+	fields := map[string]CustomField{}
+	var warnings []string
+	add := func(x *ccbxml.CustomField, f CustomField) {
+		key := strings.TrimSpace(x.Label)
+		if key == "" {
+			key = x.Name
+		}
+		fields[key] = f
+	}
+
+	for _, x := range v.UserDefinedTextFields {
+		if x != nil && x.Name != "" && strings.TrimSpace(x.Text) != "" {
+			add(x, CustomField{Name: x.Name, Type: CustomFieldText, Text: strings.TrimSpace(x.Text)})
+		}
+	}
+	for _, x := range v.UserDefinedDateFields {
+		if x == nil || x.Name == "" {
+			continue
+		}
+		date, err := parseDay(x.Date, loc)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("invalid date %q of %s", x.Date, x.Name))
+			continue
+		}
+		if date != nil {
+			add(x, CustomField{Name: x.Name, Type: CustomFieldDate, Date: date})
+		}
+	}
+	for _, x := range v.UserDefinedPulldownFields {
+		if x != nil && x.Name != "" && x.Selection != nil && strings.TrimSpace(x.Selection.Name) != "" {
+			add(x, CustomField{Name: x.Name, Type: CustomFieldPulldown, Option: &CustomFieldOption{
+				ID:   x.Selection.ID,
+				Name: strings.TrimSpace(x.Selection.Name),
+			}})
+		}
+	}
+	return fields, warnings
+}
+
+// labelCustomFields keys the user-defined fields of the individuals which have no
+// label in their profile by the label from custom_field_labels. Labels are only
+// fetched when needed, and fields keep their name if they cannot be fetched.
+func (svc *defaultService) labelCustomFields(ctx context.Context, individuals ...*Individual) {
+	var labels map[string]string
+	for _, individual := range individuals {
+		for key, f := range individual.CustomFields {
+			if key != f.Name {
+				continue // Already labelled.
+			}
+			if labels == nil {
+				list, err := svc.GetCustomFieldLabels(ctx)
+				if err != nil {
+					vouslog.GetLogger(ctx).WithError(err).Warn("Failed to get custom field labels.")
+					return
+				}
+				labels = map[string]string{}
+				for _, l := range list {
+					labels[customFieldKey(l.Name)] = l.Label
+				}
+			}
+			if label, ok := labels[customFieldKey(f.Name)]; ok {
+				delete(individual.CustomFields, key)
+				individual.CustomFields[label] = f
+			}
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package ccb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb/ccbxml"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// EventOccurrence is a single occurrence of an event on the public calendar.
+type EventOccurrence struct {
+	EventID     string    `json:"event_id,omitempty"` // Not returned by every version of CCB.
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Type        string    `json:"type,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	GroupName   string    `json:"group_name,omitempty"`
+	Grouping    string    `json:"grouping,omitempty"`
+	LeaderName  string    `json:"leader_name,omitempty"`
+}
+
+// Event is an event in CCB, which may recur.
+type Event struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description,omitempty"`
+	Start        *time.Time `json:"start,omitempty"`      // Of the first occurrence.
+	End          *time.Time `json:"end,omitempty"`        // Of the first occurrence.
+	Recurrence   string     `json:"recurrence,omitempty"` // e.g. Every week on Sunday.
+	Exceptions   []string   `json:"exceptions,omitempty"` // Dates the event does not occur on.
+	GroupID      string     `json:"group_id,omitempty"`
+	GroupName    string     `json:"group_name,omitempty"`
+	Organizer    string     `json:"organizer,omitempty"`
+	LocationName string     `json:"location_name,omitempty"`
+	Location     *Address   `json:"location,omitempty"`
+	PublicListed bool       `json:"public_listed"`
+	Created      *time.Time `json:"created,omitempty"`
+	Modified     *time.Time `json:"modified,omitempty"`
+}
+
+// ListPublicEvents returns the occurrences of events on the public calendar between
+// the dates from and to, both inclusive.
+func (svc *defaultService) ListPublicEvents(ctx context.Context, from, to time.Time) ([]EventOccurrence, error) {
+	logger := vouslog.GetLogger(ctx)
+	loc := svc.config.Location()
+	logger.WithFields(logrus.Fields{
+		"from": from.In(loc).Format(dateLayout),
+		"to":   to.In(loc).Format(dateLayout),
+	}).Info("Listing public events from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "public_calendar_listing")
+	q.Add("date_start", from.In(loc).Format(dateLayout))
+	q.Add("date_end", to.In(loc).Format(dateLayout))
+
+	occurrences := []EventOccurrence{}
+	var warnings []string
+	if err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		return ccbxml.EachItem(r, func(v *ccbxml.Item) error {
+			start, err := time.ParseInLocation(dateLayout+" 15:04:05", v.Date+" "+v.StartTime, loc)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("invalid start %q %q of %q", v.Date, v.StartTime, v.EventName))
+				return nil
+			}
+			end, err := time.ParseInLocation(dateLayout+" 15:04:05", v.Date+" "+v.EndTime, loc)
+			if err != nil || end.Before(start) {
+				end = start // Treat events without a valid end as instants.
+			}
+
+			occurrences = append(occurrences, EventOccurrence{
+				EventID:     v.EventID,
+				Name:        strings.TrimSpace(v.EventName),
+				Description: strings.TrimSpace(v.EventDescription),
+				Start:       start,
+				End:         end,
+				Type:        strings.TrimSpace(v.EventType),
+				Location:    strings.TrimSpace(v.Location),
+				GroupName:   strings.TrimSpace(v.GroupName),
+				Grouping:    strings.TrimSpace(v.GroupingName),
+				LeaderName:  strings.TrimSpace(v.LeaderName),
+			})
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	if len(warnings) > 0 {
+		logger.WithField("warnings", warnings).Warn("Malformed public events from CCB.")
+	}
+	return occurrences, nil
+}
+
+// GetEvent returns the event with the id, or ErrNotFound.
+func (svc *defaultService) GetEvent(ctx context.Context, id string) (*Event, error) {
+	vouslog.GetLogger(ctx).WithField("event_id", id).Info("Getting event from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "event_profile")
+	q.Add("id", id)
+
+	data, err := svc.callCCB(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if data.Response.Events == nil || len(data.Response.Events.Event) == 0 || data.Response.Events.Event[0] == nil {
+		return nil, ErrNotFound
+	}
+
+	v := data.Response.Events.Event[0]
+	loc := svc.config.Location()
+	e := &Event{
+		ID:           v.ID,
+		Name:         strings.TrimSpace(v.Name),
+		Description:  strings.TrimSpace(v.Description),
+		Recurrence:   strings.TrimSpace(v.RecurrenceDescription),
+		GroupID:      v.Group.ID,
+		GroupName:    strings.TrimSpace(v.Group.Name),
+		Organizer:    strings.TrimSpace(v.Organizer.Name),
+		PublicListed: v.PublicCalendarListed == "true",
+	}
+	for _, x := range v.Exceptions {
+		if x != nil && x.Date != "" {
+			e.Exceptions = append(e.Exceptions, x.Date)
+		}
+	}
+	if v.Location != nil {
+		e.LocationName = strings.TrimSpace(v.Location.Name)
+		if v.Location.StreetAddress != "" || v.Location.City != "" {
+			e.Location = &Address{
+				StreetAddress: v.Location.StreetAddress,
+				City:          v.Location.City,
+				State:         v.Location.State,
+				Zip:           v.Location.Zip,
+			}
+		}
+	}
+
+	var warnings []string
+	for _, t := range []struct {
+		name  string
+		value string
+		dst   **time.Time
+	}{
+		{"start", v.StartDateTime, &e.Start},
+		{"end", v.EndDateTime, &e.End},
+		{"created timestamp", v.Created, &e.Created},
+		{"modified timestamp", v.Modified, &e.Modified},
+	} {
+		if *t.dst, err = parseTimestamp(t.value, loc); err != nil {
+			warnings = append(warnings, fmt.Sprintf("invalid %s %q", t.name, t.value))
+		}
+	}
+	if len(warnings) > 0 {
+		vouslog.GetLogger(ctx).WithFields(logrus.Fields{
+			"event_id": id,
+			"warnings": warnings,
+		}).Warn("Malformed event from CCB.")
+	}
+	return e, nil
+}
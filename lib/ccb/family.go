@@ -0,0 +1,135 @@
+package ccb
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+)
+
+// FamilyPosition is the position of an individual in their family.
+type FamilyPosition string
+
+// The positions of individuals in families.
+const (
+	FamilyPrimaryContact FamilyPosition = "primary_contact"
+	FamilySpouse         FamilyPosition = "spouse"
+	FamilyChild          FamilyPosition = "child"
+	FamilyOther          FamilyPosition = "other"
+)
+
+// parseFamilyPosition parses a family position from CCB, which is either its name
+// or its code, e.g. "Primary Contact" or "h".
+func parseFamilyPosition(s string) FamilyPosition {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return ""
+	case "primary contact", "h":
+		return FamilyPrimaryContact
+	case "spouse", "s":
+		return FamilySpouse
+	case "child", "c":
+		return FamilyChild
+	}
+	return FamilyOther
+}
+
+// familyPositionOrder orders family members with the adults first.
+var familyPositionOrder = map[FamilyPosition]int{
+	FamilyPrimaryContact: 0,
+	FamilySpouse:         1,
+	FamilyOther:          2,
+	FamilyChild:          3,
+	"":                   4,
+}
+
+// FamilyMember is a member of the family of an individual, as listed in their
+// profile.
+type FamilyMember struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name,omitempty"`
+	Position FamilyPosition `json:"position,omitempty"`
+}
+
+// Family is a household in CCB.
+type Family struct {
+	ID       string       `json:"id"`
+	Members  []Individual `json:"members"` // Adults first.
+	Modified *time.Time   `json:"modified,omitempty"`
+}
+
+// PrimaryContact returns the primary contact of the family, or nil if it has none.
+func (f *Family) PrimaryContact() *Individual {
+	for i := range f.Members {
+		if f.Members[i].FamilyPosition == FamilyPrimaryContact {
+			return &f.Members[i]
+		}
+	}
+	return nil
+}
+
+// Parents returns the primary contact and spouse of the family, who are the people
+// to reach about a child.
+func (f *Family) Parents() []Individual {
+	var parents []Individual
+	for _, m := range f.Members {
+		if m.FamilyPosition == FamilyPrimaryContact || m.FamilyPosition == FamilySpouse {
+			parents = append(parents, m)
+		}
+	}
+	return parents
+}
+
+// GetFamily returns the family with the id, or ErrNotFound.
+func (svc *defaultService) GetFamily(ctx context.Context, id string) (*Family, error) {
+	logger := vouslog.GetLogger(ctx).WithField("family_id", id)
+	logger.Info("Getting family from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "family_detail")
+	q.Add("family_id", id)
+
+	data, err := svc.callCCB(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if data.Response.Families == nil || len(data.Response.Families.Family) == 0 || data.Response.Families.Family[0] == nil {
+		return nil, ErrNotFound
+	}
+
+	v := data.Response.Families.Family[0]
+	loc := svc.config.Location()
+	f := &Family{
+		ID:      v.ID,
+		Members: []Individual{},
+	}
+	var warnings []string
+	if f.Modified, err = parseTimestamp(v.Modified, loc); err != nil {
+		warnings = append(warnings, "invalid modified timestamp "+v.Modified)
+	}
+	for _, x := range v.Individuals {
+		if x == nil {
+			continue
+		}
+		member, w := individualFromCCB(x, loc)
+		if member.FamilyID == "" {
+			member.FamilyID = f.ID
+		}
+		f.Members = append(f.Members, *member)
+		warnings = append(warnings, w...)
+	}
+	if len(warnings) > 0 {
+		logger.WithField("warnings", warnings).Warn("Malformed family from CCB.")
+	}
+
+	for i := range f.Members {
+		svc.labelCustomFields(ctx, &f.Members[i])
+	}
+	sort.SliceStable(f.Members, func(i, j int) bool {
+		return familyPositionOrder[f.Members[i].FamilyPosition] < familyPositionOrder[f.Members[j].FamilyPosition]
+	})
+	return f, nil
+}
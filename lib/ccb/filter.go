@@ -0,0 +1,129 @@
+package ccb
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// FormResponseFilter filters form responses after they are fetched from CCB, for
+// criteria the form_responses service cannot filter on. Names and values are
+// compared case insensitively. Zero value fields are ignored.
+type FormResponseFilter struct {
+	Answers        map[string]string // Question title to one of the answers it must have.
+	Profile        map[string]string // Profile field name to the value it must have.
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+	ModifiedSince  *time.Time // Inclusive, to the second unlike GetFormResponsesRequest.ModifiedSince.
+	ModifiedBefore *time.Time
+	PaymentStatus  []PaymentStatus // The payment must have one of the statuses. Responses without a payment never match.
+}
+
+// IsEmpty returns true if the filter matches everything.
+func (f FormResponseFilter) IsEmpty() bool {
+	return len(f.Answers) == 0 && len(f.Profile) == 0 &&
+		f.CreatedAfter == nil && f.CreatedBefore == nil &&
+		f.ModifiedSince == nil && f.ModifiedBefore == nil &&
+		len(f.PaymentStatus) == 0
+}
+
+// Match returns true if the form response matches every criteria of the filter.
+// Responses without timestamps, or with timestamps which could not be parsed,
+// never match date criteria.
+func (f FormResponseFilter) Match(r FormResponse) bool {
+	for question, want := range f.Answers {
+		if !containsFold(r.Values(question), want) {
+			return false
+		}
+	}
+	for field, want := range f.Profile {
+		if got, ok := lookupFold(r.ProfileInfo, field); !ok || !strings.EqualFold(got, want) {
+			return false
+		}
+	}
+
+	if len(f.PaymentStatus) > 0 && (r.Payment == nil || !hasPaymentStatus(f.PaymentStatus, r.Payment.Status)) {
+		return false
+	}
+
+	if f.CreatedAfter != nil || f.CreatedBefore != nil {
+		if r.Created == nil {
+			return false
+		}
+		if f.CreatedAfter != nil && !r.Created.After(*f.CreatedAfter) {
+			return false
+		}
+		if f.CreatedBefore != nil && !r.Created.Before(*f.CreatedBefore) {
+			return false
+		}
+	}
+
+	if f.ModifiedSince != nil || f.ModifiedBefore != nil {
+		if r.Modified == nil {
+			return false
+		}
+		if f.ModifiedSince != nil && r.Modified.Before(*f.ModifiedSince) {
+			return false
+		}
+		if f.ModifiedBefore != nil && !r.Modified.Before(*f.ModifiedBefore) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lookupFold returns the value for the key in m, matching the key case insensitively.
+func lookupFold(m map[string]string, key string) (string, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// containsFold returns true if values contains want, compared case insensitively.
+func containsFold(values []string, want string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPaymentStatus returns true if statuses contains status.
+func hasPaymentStatus(statuses []PaymentStatus, status PaymentStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterFormResponsesResponse represents a response from FilterFormResponses.
+type FilterFormResponsesResponse struct {
+	Responses []FormResponse
+	Scanned   int // Number of form responses fetched from CCB before filtering.
+}
+
+// FilterFormResponses pages through every form response matching req and returns
+// the ones matching the filter.
+func FilterFormResponses(ctx context.Context, svc Service, req GetFormResponsesRequest, filter FormResponseFilter) (*FilterFormResponsesResponse, error) {
+	resp := &FilterFormResponsesResponse{}
+	if err := EachFormResponse(ctx, svc, req, func(r FormResponse) error {
+		resp.Scanned++
+		if filter.Match(r) {
+			resp.Responses = append(resp.Responses, r)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
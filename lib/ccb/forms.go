@@ -0,0 +1,105 @@
+package ccb
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// FormsConfig configures the forms and campuses known to the API. Adding a campus
+// or a form is a matter of adding it here.
+type FormsConfig struct {
+	// FormIDs maps a form type and campus slug, separated by a slash, to the CCB form
+	// id. Forms which are not specific to a campus have no slash, e.g. retreat:120.
+	// Only forms whose CCB id is known are in the default.
+	FormIDs map[string]FormID `envconfig:"CCB_FORMS" default:"connect_card/jdd:85"`
+
+	// CampusSlugs maps CCB campus ids to slugs. Campuses not in it use their
+	// slugified name. Once it is set, the campus of every form must be one of these
+	// slugs, so form campuses match the campuses of records from CCB.
+	CampusSlugs map[string]string `envconfig:"CCB_CAMPUS_SLUGS"`
+}
+
+// Validate returns an error if a form has no CCB id, or if CampusSlugs is set and
+// a form has a campus which is not in it. The default config is valid, so
+// deployments which do not set CampusSlugs keep starting.
+func (cfg FormsConfig) Validate() error {
+	slugs := map[string]bool{}
+	for _, slug := range cfg.CampusSlugs {
+		slugs[slug] = true
+	}
+	for key, id := range cfg.FormIDs {
+		if id <= 0 {
+			return errors.New("form " + key + " has no CCB form id")
+		}
+		if i := strings.Index(key, "/"); i >= 0 && len(slugs) > 0 && !slugs[key[i+1:]] {
+			return errors.New("campus of form " + key + " is not in CCB_CAMPUS_SLUGS")
+		}
+	}
+	return nil
+}
+
+// Form is a CCB form, identified in the API by its type and campus.
+type Form struct {
+	Type   string `json:"type"`             // e.g. connect_card.
+	Campus string `json:"campus,omitempty"` // Campus slug, empty for forms shared by every campus.
+	ID     FormID `json:"id"`
+}
+
+// Forms is a list of forms, sorted by type and campus.
+type Forms []Form
+
+// Forms returns the configured forms.
+func (cfg FormsConfig) Forms() Forms {
+	forms := make(Forms, 0, len(cfg.FormIDs))
+	for key, id := range cfg.FormIDs {
+		f := Form{Type: key, ID: id}
+		if i := strings.Index(key, "/"); i >= 0 {
+			f.Type, f.Campus = key[:i], key[i+1:]
+		}
+		forms = append(forms, f)
+	}
+	sort.Slice(forms, func(i, j int) bool {
+		if forms[i].Type != forms[j].Type {
+			return forms[i].Type < forms[j].Type
+		}
+		return forms[i].Campus < forms[j].Campus
+	})
+	return forms
+}
+
+// Find returns the forms of the type for the campus, or for every campus if
+// campus is empty.
+func (forms Forms) Find(typ, campus string) Forms {
+	var found Forms
+	for _, f := range forms {
+		if f.Type == typ && (campus == "" || f.Campus == campus) {
+			found = append(found, f)
+		}
+	}
+	return found
+}
+
+// ByID returns the form with the CCB form id.
+func (forms Forms) ByID(id FormID) (Form, bool) {
+	for _, f := range forms {
+		if f.ID == id {
+			return f, true
+		}
+	}
+	return Form{}, false
+}
+
+// Campuses returns the slugs of the campuses which have forms.
+func (forms Forms) Campuses() []string {
+	seen := map[string]bool{}
+	var campuses []string
+	for _, f := range forms {
+		if f.Campus != "" && !seen[f.Campus] {
+			seen[f.Campus] = true
+			campuses = append(campuses, f.Campus)
+		}
+	}
+	sort.Strings(campuses)
+	return campuses
+}
@@ -0,0 +1,67 @@
+package ccb
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+func TestFormsConfigDefaultValid(t *testing.T) {
+	for _, env := range []string{"CCB_FORMS", "CCB_CAMPUS_SLUGS"} {
+		if v, ok := os.LookupEnv(env); ok {
+			os.Unsetenv(env)
+			defer os.Setenv(env, v)
+		}
+	}
+
+	var cfg FormsConfig
+	if err := envconfig.Process("", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.FormIDs) == 0 {
+		t.Fatal("no forms in the default config")
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("default config is invalid: %v", err)
+	}
+}
+
+func TestFormsConfigValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   FormsConfig
+		valid bool
+	}{
+		{
+			name:  "campus in slugs",
+			cfg:   FormsConfig{FormIDs: map[string]FormID{"connect_card/jdd": 85}, CampusSlugs: map[string]string{"2": "jdd"}},
+			valid: true,
+		},
+		{
+			name:  "campus missing from slugs",
+			cfg:   FormsConfig{FormIDs: map[string]FormID{"connect_card/itech": 90}, CampusSlugs: map[string]string{"2": "jdd"}},
+			valid: false,
+		},
+		{
+			name:  "no slugs",
+			cfg:   FormsConfig{FormIDs: map[string]FormID{"connect_card/itech": 90}},
+			valid: true,
+		},
+		{
+			name:  "form without a campus",
+			cfg:   FormsConfig{FormIDs: map[string]FormID{"retreat": 120}, CampusSlugs: map[string]string{"2": "jdd"}},
+			valid: true,
+		},
+		{
+			name:  "placeholder id",
+			cfg:   FormsConfig{FormIDs: map[string]FormID{"connect_card/jdd": 0}},
+			valid: false,
+		},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%s: Validate() = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
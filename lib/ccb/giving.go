@@ -0,0 +1,168 @@
+package ccb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb/ccbxml"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// Batch is a batch of giving transactions in CCB. Batches and transactions leave
+// out who gave, as giving is only reported in aggregate.
+type Batch struct {
+	ID           string        `json:"id"`
+	CampusID     string        `json:"campus_id,omitempty"`
+	Campus       string        `json:"campus,omitempty"` // Campus slug.
+	PostDate     *time.Time    `json:"post_date,omitempty"`
+	Status       string        `json:"status,omitempty"`
+	Source       string        `json:"source,omitempty"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+// Transaction is a gift, split into details by fund.
+type Transaction struct {
+	ID          string              `json:"id"`
+	CampusID    string              `json:"campus_id,omitempty"`
+	Campus      string              `json:"campus,omitempty"` // Campus slug.
+	Date        *time.Time          `json:"date,omitempty"`
+	PaymentType string              `json:"payment_type,omitempty"`
+	Details     []TransactionDetail `json:"details"`
+}
+
+// TransactionDetail is the part of a transaction given to a fund.
+type TransactionDetail struct {
+	FundID        string `json:"fund_id"`
+	Fund          string `json:"fund"`
+	Amount        Money  `json:"amount"`
+	TaxDeductible bool   `json:"tax_deductible"`
+}
+
+// TransactionDetailType is a fund transactions can be given to, which CCB calls a
+// transaction detail type.
+type TransactionDetailType struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	ParentID      string `json:"parent_id,omitempty"`
+	TaxDeductible bool   `json:"tax_deductible"`
+	Active        bool   `json:"active"`
+}
+
+// ListBatches returns the batches posted between the dates from and to, both
+// inclusive, using batch_profiles_in_date_range.
+func (svc *defaultService) ListBatches(ctx context.Context, from, to time.Time) ([]Batch, error) {
+	logger := vouslog.GetLogger(ctx)
+	loc := svc.config.Location()
+	logger.WithFields(logrus.Fields{
+		"from": from.In(loc).Format(dateLayout),
+		"to":   to.In(loc).Format(dateLayout),
+	}).Info("Listing batches from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "batch_profiles_in_date_range")
+	q.Add("date_start", from.In(loc).Format(dateLayout))
+	q.Add("date_end", to.In(loc).Format(dateLayout))
+
+	batches := []Batch{}
+	var warnings []string
+	if err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		return ccbxml.EachBatch(r, func(v *ccbxml.Batch) error {
+			b, w := svc.batchFromCCB(v, loc)
+			batches = append(batches, *b)
+			warnings = append(warnings, w...)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	if len(warnings) > 0 {
+		logger.WithField("warnings", warnings).Warn("Malformed batches from CCB.")
+	}
+	return batches, nil
+}
+
+// ListTransactionDetailTypes returns every fund.
+func (svc *defaultService) ListTransactionDetailTypes(ctx context.Context) ([]TransactionDetailType, error) {
+	vouslog.GetLogger(ctx).Info("Listing transaction detail types from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "transaction_detail_type_list")
+
+	types := []TransactionDetailType{}
+	if err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		return ccbxml.EachTransactionDetailType(r, func(v *ccbxml.TransactionDetailType) error {
+			types = append(types, TransactionDetailType{
+				ID:            v.ID,
+				Name:          strings.TrimSpace(v.Name),
+				ParentID:      v.Parent.ID,
+				TaxDeductible: v.TaxDeductible == "true",
+				Active:        v.Active == "true",
+			})
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+// batchFromCCB converts a batch, returning warnings about values which could not be
+// parsed. Transaction details with invalid amounts are left out.
+func (svc *defaultService) batchFromCCB(v *ccbxml.Batch, loc *time.Location) (*Batch, []string) {
+	var warnings []string
+	b := &Batch{
+		ID:           v.ID,
+		CampusID:     v.Campus.ID,
+		Status:       strings.TrimSpace(v.Status),
+		Source:       strings.TrimSpace(v.Source),
+		Transactions: []Transaction{},
+	}
+	if v.Campus.ID != "" {
+		b.Campus = svc.campusSlug(v.Campus.ID, v.Campus.Name)
+	}
+	var err error
+	if b.PostDate, err = parseDay(v.PostDate, loc); err != nil {
+		warnings = append(warnings, fmt.Sprintf("invalid post date %q of batch %s", v.PostDate, v.ID))
+	}
+
+	for _, x := range v.Transactions {
+		if x == nil {
+			continue
+		}
+		t := Transaction{
+			ID:          x.ID,
+			CampusID:    x.Campus.ID,
+			PaymentType: strings.TrimSpace(x.PaymentType),
+			Details:     []TransactionDetail{},
+		}
+		if x.Campus.ID != "" {
+			t.Campus = svc.campusSlug(x.Campus.ID, x.Campus.Name)
+		}
+		if t.Date, err = parseDay(x.Date, loc); err != nil {
+			warnings = append(warnings, fmt.Sprintf("invalid date %q of transaction %s", x.Date, x.ID))
+		}
+		for _, d := range x.Details {
+			if d == nil {
+				continue
+			}
+			amount, err := ParseMoney(d.Amount)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("invalid amount %q of transaction %s", d.Amount, x.ID))
+				continue
+			}
+			t.Details = append(t.Details, TransactionDetail{
+				FundID:        d.Fund.ID,
+				Fund:          strings.TrimSpace(d.Fund.Name),
+				Amount:        amount,
+				TaxDeductible: d.TaxDeductible == "true",
+			})
+		}
+		b.Transactions = append(b.Transactions, t)
+	}
+	return b, warnings
+}
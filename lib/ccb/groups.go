@@ -0,0 +1,359 @@
+package ccb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb/ccbxml"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// Group is a group in CCB, such as a small group.
+type Group struct {
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	Description    string         `json:"description,omitempty"`
+	Type           string         `json:"type,omitempty"` // e.g. Small Group.
+	Department     string         `json:"department,omitempty"`
+	Area           string         `json:"area,omitempty"` // Area of town the group meets in.
+	CampusID       string         `json:"campus_id,omitempty"`
+	Campus         string         `json:"campus,omitempty"` // Campus slug.
+	Leader         *GroupLeader   `json:"leader,omitempty"`
+	Schedule       *GroupSchedule `json:"schedule,omitempty"`
+	Location       *Address       `json:"location,omitempty"`
+	Capacity       *int           `json:"capacity"` // Null if unlimited.
+	CurrentMembers int            `json:"current_members"`
+	MembershipType string         `json:"membership_type,omitempty"` // e.g. Open to All.
+	Childcare      bool           `json:"childcare"`
+	Listed         bool           `json:"listed"`        // Listed in the CCB group search.
+	PublicListed   bool           `json:"public_listed"` // Listed in the public CCB group search.
+	Active         bool           `json:"active"`
+	Created        *time.Time     `json:"created,omitempty"`
+	Modified       *time.Time     `json:"modified,omitempty"`
+}
+
+// IsFull returns true if the group has a capacity and has reached it.
+func (g Group) IsFull() bool {
+	return g.Capacity != nil && g.CurrentMembers >= *g.Capacity
+}
+
+// GroupLeader is the main leader of a group.
+type GroupLeader struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Email  string  `json:"email,omitempty"`
+	Phones []Phone `json:"phones,omitempty"`
+}
+
+// GroupSchedule is when a group meets.
+type GroupSchedule struct {
+	Day  string `json:"day,omitempty"`  // e.g. Tuesday.
+	Time string `json:"time,omitempty"` // e.g. 7:00 PM.
+}
+
+// GroupParticipant is a member of a group.
+type GroupParticipant struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	FirstName string     `json:"first_name,omitempty"`
+	LastName  string     `json:"last_name,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	Phones    []Phone    `json:"phones,omitempty"`
+	Status    string     `json:"status,omitempty"` // e.g. Member, Leader.
+	Created   *time.Time `json:"created,omitempty"`
+	Modified  *time.Time `json:"modified,omitempty"`
+}
+
+// ListGroupsRequest represents a request to ListGroups.
+type ListGroupsRequest struct {
+	ModifiedSince *time.Time // Only the day is sent to CCB.
+	Page          int
+	PageSize      int
+}
+
+// ListGroupsResponse represents a response from ListGroups.
+type ListGroupsResponse struct {
+	Groups []Group
+}
+
+// ListGroups returns a page of groups, without their participants.
+func (svc *defaultService) ListGroups(ctx context.Context, req ListGroupsRequest) (*ListGroupsResponse, error) {
+	var groups []Group
+	if _, err := svc.StreamGroups(ctx, req, func(g Group) error {
+		groups = append(groups, g)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &ListGroupsResponse{Groups: groups}, nil
+}
+
+// StreamGroups calls fn with each group of the page as it is read from CCB.
+func (svc *defaultService) StreamGroups(ctx context.Context, req ListGroupsRequest, fn func(Group) error) (int, error) {
+	logger := vouslog.GetLogger(ctx)
+	logger.WithFields(logrus.Fields{
+		"page":      req.Page,
+		"page_size": req.PageSize,
+	}).Info("Listing groups from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "group_profiles")
+	q.Add("include_participants", "false")
+	q.Add("page", strconv.Itoa(req.Page))
+	q.Add("per_page", strconv.Itoa(req.PageSize))
+	if req.ModifiedSince != nil {
+		q.Add("modified_since", req.ModifiedSince.In(svc.config.Location()).Format(dateLayout))
+	}
+
+	var n int
+	err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		return ccbxml.EachGroup(r, func(v *ccbxml.Group) error {
+			n++
+			return recordErr(fn(svc.groupFromCCB(ctx, v)))
+		})
+	})
+	return n, err
+}
+
+// GetGroup returns the group with the id, or ErrNotFound.
+func (svc *defaultService) GetGroup(ctx context.Context, id string) (*Group, error) {
+	vouslog.GetLogger(ctx).WithField("group_id", id).Info("Getting group from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "group_profile_from_id")
+	q.Add("id", id)
+	q.Add("include_participants", "false")
+
+	data, err := svc.callCCB(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := svc.groupsFromCCB(ctx, data)
+	if len(groups) == 0 {
+		return nil, ErrNotFound
+	}
+	return &groups[0], nil
+}
+
+// ListGroupParticipants returns the participants of the group, or ErrNotFound if
+// the group does not exist.
+func (svc *defaultService) ListGroupParticipants(ctx context.Context, groupID string) ([]GroupParticipant, error) {
+	vouslog.GetLogger(ctx).WithField("group_id", groupID).Info("Listing group participants from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "group_participants")
+	q.Add("id", groupID)
+
+	// The response has the one group, so its participants are built as they're read.
+	loc := svc.config.Location()
+	found := false
+	participants := []GroupParticipant{}
+	if err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		return ccbxml.EachGroupParticipant(r, func(string) error {
+			found = true
+			return nil
+		}, func(p *ccbxml.Participant) error {
+			participant := GroupParticipant{
+				ID:        p.ID,
+				Name:      strings.TrimSpace(p.Name),
+				FirstName: p.FirstName,
+				LastName:  p.LastName,
+				Email:     p.Email,
+				Phones:    phonesFromCCB(p.Phones),
+				Status:    strings.TrimSpace(p.Status),
+			}
+			// Timestamps are informational, so unparsable ones are left out.
+			participant.Created, _ = parseTimestamp(p.Created, loc)
+			participant.Modified, _ = parseTimestamp(p.Modified, loc)
+			participants = append(participants, participant)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrNotFound
+	}
+	return participants, nil
+}
+
+// EachGroup calls fn for every group matching req, fetching page after page from
+// CCB like EachFormResponse. fn can return StopPaging to stop early.
+func EachGroup(ctx context.Context, svc Service, req ListGroupsRequest, fn func(Group) error) error {
+	return eachPage(&req.Page, &req.PageSize, func() (int, error) {
+		var fnErr error
+		n, err := svc.StreamGroups(ctx, req, func(g Group) error {
+			fnErr = fn(g)
+			return fnErr
+		})
+		if fnErr != nil {
+			return n, fnErr
+		}
+		if err != nil {
+			return n, errors.New("list groups page " + strconv.Itoa(req.Page) + ": " + err.Error())
+		}
+		return n, nil
+	})
+}
+
+// groupsFromCCB builds the groups from a response listing groups.
+func (svc *defaultService) groupsFromCCB(ctx context.Context, data *ccbxml.Response) []Group {
+	if data.Response.Groups == nil {
+		return nil
+	}
+
+	var groups []Group
+	for _, v := range data.Response.Groups.Group {
+		if v != nil {
+			groups = append(groups, svc.groupFromCCB(ctx, v))
+		}
+	}
+	return groups
+}
+
+// groupFromCCB builds the group from a record of a group_profiles response,
+// logging any warnings.
+func (svc *defaultService) groupFromCCB(ctx context.Context, v *ccbxml.Group) Group {
+	loc := svc.config.Location()
+	var warnings []string
+	g := Group{
+		ID:             v.ID,
+		Name:           strings.TrimSpace(v.Name),
+		Description:    strings.TrimSpace(v.Description),
+		Type:           v.GroupType.Name,
+		Department:     v.Department.Name,
+		Area:           v.Area.Name,
+		MembershipType: v.MembershipType.Name,
+		Childcare:      v.ChildcareProvided == "true",
+		Listed:         v.Listed == "true",
+		PublicListed:   v.PublicSearchListed == "true",
+		Active:         v.Inactive != "true",
+	}
+	if v.Campus.ID != "" {
+		g.CampusID = v.Campus.ID
+		g.Campus = svc.campusSlug(v.Campus.ID, v.Campus.Name)
+	}
+
+	if v.MainLeader != nil && v.MainLeader.ID != "" {
+		g.Leader = &GroupLeader{
+			ID:     v.MainLeader.ID,
+			Name:   strings.TrimSpace(v.MainLeader.FullName),
+			Email:  v.MainLeader.Email,
+			Phones: phonesFromCCB(v.MainLeader.Phones),
+		}
+	}
+
+	if day, at := strings.TrimSpace(v.MeetingDay.Name), strings.TrimSpace(v.MeetingTime.Name); day != "" || at != "" {
+		g.Schedule = &GroupSchedule{Day: day, Time: at}
+	}
+
+	for _, a := range v.Addresses {
+		if a == nil || (a.StreetAddress == "" && a.City == "") {
+			continue
+		}
+		g.Location = &Address{
+			Type:          a.Type,
+			StreetAddress: a.StreetAddress,
+			City:          a.City,
+			State:         a.State,
+			Zip:           a.Zip,
+		}
+		if a.Type == "meeting" {
+			break // Prefer the meeting address.
+		}
+	}
+
+	if capacity := strings.TrimSpace(v.GroupCapacity); capacity != "" && !strings.EqualFold(capacity, "unlimited") {
+		if n, err := strconv.Atoi(capacity); err == nil {
+			g.Capacity = &n
+		} else {
+			warnings = append(warnings, fmt.Sprintf("invalid capacity %q", capacity))
+		}
+	}
+	if members := strings.TrimSpace(v.CurrentMembers); members != "" {
+		if n, err := strconv.Atoi(members); err == nil {
+			g.CurrentMembers = n
+		} else {
+			warnings = append(warnings, fmt.Sprintf("invalid current members %q", members))
+		}
+	}
+
+	var err error
+	if g.Created, err = parseTimestamp(v.Created, loc); err != nil {
+		warnings = append(warnings, fmt.Sprintf("invalid created timestamp %q", v.Created))
+	}
+	if g.Modified, err = parseTimestamp(v.Modified, loc); err != nil {
+		warnings = append(warnings, fmt.Sprintf("invalid modified timestamp %q", v.Modified))
+	}
+
+	if len(warnings) > 0 {
+		vouslog.GetLogger(ctx).WithFields(logrus.Fields{
+			"group_id": v.ID,
+			"warnings": warnings,
+		}).Warn("Malformed group from CCB.")
+	}
+	return g
+}
+
+// phonesFromCCB builds the phone numbers, leaving out empty ones.
+func phonesFromCCB(phones []*ccbxml.Phone) []Phone {
+	var out []Phone
+	for _, p := range phones {
+		if p != nil && strings.TrimSpace(p.Number) != "" {
+			out = append(out, Phone{Type: p.Type, Number: strings.TrimSpace(p.Number)})
+		}
+	}
+	return out
+}
+
+// GroupMembershipStatus is how an individual is added to a group.
+type GroupMembershipStatus string
+
+// GroupMembershipStatus values.
+const (
+	GroupMembershipAdd     GroupMembershipStatus = "add"     // Added as a member.
+	GroupMembershipInvite  GroupMembershipStatus = "invite"  // Invited to join.
+	GroupMembershipRequest GroupMembershipStatus = "request" // Requesting to join, pending approval by a leader.
+)
+
+// AddIndividualToGroup adds the individual to the group with the status.
+func (svc *defaultService) AddIndividualToGroup(ctx context.Context, groupID, individualID string, status GroupMembershipStatus) error {
+	vouslog.GetLogger(ctx).WithFields(logrus.Fields{
+		"group_id":      groupID,
+		"individual_id": individualID,
+		"status":        status,
+	}).Info("Adding individual to group in CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "add_individual_to_group")
+	q.Add("id", individualID)
+	q.Add("group_id", groupID)
+	q.Add("status", string(status))
+
+	_, err := svc.postCCB(ctx, q)
+	return err
+}
+
+// RemoveIndividualFromGroup removes the individual from the group.
+func (svc *defaultService) RemoveIndividualFromGroup(ctx context.Context, groupID, individualID string) error {
+	vouslog.GetLogger(ctx).WithFields(logrus.Fields{
+		"group_id":      groupID,
+		"individual_id": individualID,
+	}).Info("Removing individual from group in CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "remove_individual_from_group")
+	q.Add("id", individualID)
+	q.Add("group_id", groupID)
+
+	_, err := svc.postCCB(ctx, q)
+	return err
+}
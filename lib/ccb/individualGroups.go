@@ -0,0 +1,109 @@
+package ccb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+)
+
+// GroupRole is the role of an individual in a group.
+type GroupRole string
+
+// The roles of individuals in groups.
+const (
+	GroupRoleLeader GroupRole = "leader"
+	GroupRoleMember GroupRole = "member"
+)
+
+// parseGroupRole parses the status of a participant, e.g. "Main Leader",
+// "Leader" or "Member".
+func parseGroupRole(s string) GroupRole {
+	if strings.Contains(strings.ToLower(s), "leader") {
+		return GroupRoleLeader
+	}
+	return GroupRoleMember
+}
+
+// isPendingStatus returns whether the status of a participant is an invitation or
+// a request to join, e.g. "Invited" or "Requesting to join", which a leader or the
+// individual has not accepted yet.
+func isPendingStatus(s string) bool {
+	s = strings.ToLower(s)
+	return strings.Contains(s, "invite") || strings.Contains(s, "request")
+}
+
+// IndividualGroup is a group an individual is in, including the groups they
+// serve in as a leader and the ones they are invited to or asked to join.
+type IndividualGroup struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Type       string     `json:"type,omitempty"` // e.g. Small Group.
+	Department string     `json:"department,omitempty"`
+	CampusID   string     `json:"campus_id,omitempty"`
+	Campus     string     `json:"campus,omitempty"` // Campus slug.
+	Role       GroupRole  `json:"role"`
+	Status     string     `json:"status,omitempty"`  // As in CCB, e.g. Main Leader.
+	Pending    bool       `json:"pending,omitempty"` // Invited or requesting to join, so not yet in the group.
+	Joined     *time.Time `json:"joined,omitempty"`
+}
+
+// GetIndividualGroups returns the groups the individual is in, the ones they lead
+// first and then by name, or ErrNotFound.
+func (svc *defaultService) GetIndividualGroups(ctx context.Context, individualID string) ([]IndividualGroup, error) {
+	logger := vouslog.GetLogger(ctx).WithField("individual_id", individualID)
+	logger.Info("Getting individual groups from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "individual_groups")
+	q.Add("individual_id", individualID)
+
+	data, err := svc.callCCB(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if data.Response.Individuals == nil || len(data.Response.Individuals.Individual) == 0 || data.Response.Individuals.Individual[0] == nil {
+		return nil, ErrNotFound
+	}
+
+	loc := svc.config.Location()
+	groups := []IndividualGroup{}
+	var warnings []string
+	for _, v := range data.Response.Individuals.Individual[0].Groups {
+		if v == nil || v.ID == "" {
+			continue
+		}
+		g := IndividualGroup{
+			ID:         v.ID,
+			Name:       strings.TrimSpace(v.Name),
+			Type:       strings.TrimSpace(v.GroupType.Name),
+			Department: strings.TrimSpace(v.Department.Name),
+			CampusID:   v.Campus.ID,
+			Role:       parseGroupRole(v.Status.Name),
+			Status:     strings.TrimSpace(v.Status.Name),
+			Pending:    isPendingStatus(v.Status.Name),
+		}
+		if v.Campus.ID != "" {
+			g.Campus = svc.campusSlug(v.Campus.ID, v.Campus.Name)
+		}
+		if g.Joined, err = parseDay(v.Joined, loc); err != nil {
+			warnings = append(warnings, fmt.Sprintf("invalid join date %q of group %s", v.Joined, v.ID))
+		}
+		groups = append(groups, g)
+	}
+	if len(warnings) > 0 {
+		logger.WithField("warnings", warnings).Warn("Malformed individual groups from CCB.")
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Role != groups[j].Role {
+			return groups[i].Role == GroupRoleLeader
+		}
+		return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+	})
+	return groups, nil
+}
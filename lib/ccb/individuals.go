@@ -0,0 +1,349 @@
+package ccb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb/ccbxml"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// maxConcurrentFetches limits the number of concurrent calls made to CCB when
+// fetching several records, to stay within the CCB rate limit.
+const maxConcurrentFetches = 4
+
+// Individual represents a person in CCB.
+type Individual struct {
+	ID               string                 `json:"id"`
+	FirstName        string                 `json:"first_name,omitempty"`
+	MiddleName       string                 `json:"middle_name,omitempty"`
+	LastName         string                 `json:"last_name,omitempty"`
+	FullName         string                 `json:"full_name,omitempty"`
+	Email            string                 `json:"email,omitempty"`
+	Phones           []Phone                `json:"phones,omitempty"`
+	Addresses        []Address              `json:"addresses,omitempty"`
+	Gender           string                 `json:"gender,omitempty"`
+	MaritalStatus    string                 `json:"marital_status,omitempty"`
+	Birthday         *Date                  `json:"birthday,omitempty"`
+	CampusID         string                 `json:"campus_id,omitempty"`
+	CampusName       string                 `json:"campus_name,omitempty"`
+	FamilyID         string                 `json:"family_id,omitempty"`
+	FamilyPosition   FamilyPosition         `json:"family_position,omitempty"`
+	FamilyMembers    []FamilyMember         `json:"family_members,omitempty"` // Other members of the family.
+	MembershipTypeID string                 `json:"membership_type_id,omitempty"`
+	MembershipDate   *time.Time             `json:"membership_date,omitempty"`
+	Active           bool                   `json:"active"`
+	CustomFields     map[string]CustomField `json:"custom_fields,omitempty"` // User-defined fields by label.
+	Created          *time.Time             `json:"created,omitempty"`
+	Modified         *time.Time             `json:"modified,omitempty"`
+}
+
+// Phone is a phone number of an individual.
+type Phone struct {
+	Type   string `json:"type"` // e.g. mobile, home, work, emergency.
+	Number string `json:"number"`
+}
+
+// Address is a postal address of an individual.
+type Address struct {
+	Type          string `json:"type"` // e.g. mailing, home, work, other.
+	StreetAddress string `json:"street_address,omitempty"`
+	City          string `json:"city,omitempty"`
+	State         string `json:"state,omitempty"`
+	Zip           string `json:"zip,omitempty"`
+	Country       string `json:"country,omitempty"`
+}
+
+// GetIndividual returns the individual with the id, or ErrNotFound.
+func (svc *defaultService) GetIndividual(ctx context.Context, id string) (*Individual, error) {
+	logger := vouslog.GetLogger(ctx)
+	logger.WithField("individual_id", id).Info("Getting individual from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "individual_profile_from_id")
+	q.Add("individual_id", id)
+
+	data, err := svc.callCCB(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Response.Individuals == nil || len(data.Response.Individuals.Individual) == 0 || data.Response.Individuals.Individual[0] == nil {
+		return nil, ErrNotFound
+	}
+	individual, warnings := individualFromCCB(data.Response.Individuals.Individual[0], svc.config.Location())
+	if len(warnings) > 0 {
+		logger.WithFields(logrus.Fields{
+			"individual_id": id,
+			"warnings":      warnings,
+		}).Warn("Malformed individual from CCB.")
+	}
+	svc.labelCustomFields(ctx, individual)
+	return individual, nil
+}
+
+// GetIndividuals fetches the individuals with the ids, deduplicating them and
+// fetching a few at a time. Individuals which are not found are left out of the
+// returned map, which is keyed by id.
+func GetIndividuals(ctx context.Context, svc Service, ids []string) (map[string]*Individual, error) {
+	unique := map[string]bool{}
+	for _, id := range ids {
+		if id != "" {
+			unique[id] = true
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		found    = make(map[string]*Individual, len(unique))
+		sem      = make(chan struct{}, maxConcurrentFetches)
+	)
+	for id := range unique {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			individual, err := svc.GetIndividual(ctx, id)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == ErrNotFound:
+				vouslog.GetLogger(ctx).WithField("individual_id", id).Warn("Individual not found in CCB.")
+			case err != nil:
+				if firstErr == nil {
+					firstErr = errors.New("get individual " + id + ": " + err.Error())
+				}
+			default:
+				found[id] = individual
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	vouslog.GetLogger(ctx).WithFields(logrus.Fields{
+		"requested": len(unique),
+		"found":     len(found),
+	}).Debug("Fetched individuals from CCB.")
+	return found, nil
+}
+
+// individualFromCCB builds an Individual from its CCB representation, parsing
+// dates and timestamps in the time zone loc. Values which cannot be parsed are left
+// out and reported as warnings.
+func individualFromCCB(v *ccbxml.Individual, loc *time.Location) (*Individual, []string) {
+	var warnings []string
+	parse := func(name, value string, parse func(string, *time.Location) (*time.Time, error)) *time.Time {
+		t, err := parse(value, loc)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("invalid %s %q", name, value))
+		}
+		return t
+	}
+
+	// Birthdays are calendar dates like those of contact details, not times.
+	var birthday *Date
+	if v.Birthday != "" && v.Birthday != "0000-00-00" {
+		if d, ok := parseDate(v.Birthday, []string{dateLayout}); ok {
+			birthday = &d
+		} else {
+			warnings = append(warnings, fmt.Sprintf("invalid birthday %q", v.Birthday))
+		}
+	}
+
+	i := &Individual{
+		ID:             v.ID,
+		FirstName:      v.FirstName,
+		MiddleName:     v.MiddleName,
+		LastName:       v.LastName,
+		FullName:       v.FullName,
+		Email:          v.Email,
+		Gender:         v.Gender,
+		MaritalStatus:  v.MaritalStatus,
+		Birthday:       birthday,
+		FamilyPosition: parseFamilyPosition(v.FamilyPosition),
+		MembershipDate: parse("membership date", v.MembershipDate, parseDay),
+		Active:         v.Active == "true",
+		Created:        parse("created timestamp", v.Created, parseTimestamp),
+		Modified:       parse("modified timestamp", v.Modified, parseTimestamp),
+	}
+	if v.Campus != nil {
+		i.CampusID = v.Campus.ID
+		i.CampusName = strings.TrimSpace(v.Campus.Name)
+	}
+	if v.Family != nil {
+		i.FamilyID = v.Family.ID
+	}
+	if v.MembershipType != nil {
+		i.MembershipTypeID = v.MembershipType.ID
+	}
+	if fields, w := customFieldsFromCCB(v, loc); len(fields) > 0 || len(w) > 0 {
+		i.CustomFields = fields
+		warnings = append(warnings, w...)
+	}
+	for _, m := range v.FamilyMembers {
+		if m != nil && m.Individual.ID != "" {
+			i.FamilyMembers = append(i.FamilyMembers, FamilyMember{
+				ID:       m.Individual.ID,
+				Name:     strings.TrimSpace(m.Individual.Name),
+				Position: parseFamilyPosition(m.FamilyPosition),
+			})
+		}
+	}
+
+	for _, p := range v.Phones {
+		if p != nil && p.Number != "" {
+			i.Phones = append(i.Phones, Phone{Type: p.Type, Number: p.Number})
+		}
+	}
+
+	for _, a := range v.Addresses {
+		if a == nil {
+			continue
+		}
+		addr := Address{
+			Type:          a.Type,
+			StreetAddress: a.StreetAddress,
+			City:          a.City,
+			State:         a.State,
+			Zip:           a.Zip,
+		}
+		if a.Country != nil {
+			addr.Country = a.Country.Code
+		}
+		i.Addresses = append(i.Addresses, addr)
+	}
+
+	return i, warnings
+}
+
+// SearchIndividualsRequest represents a request to SearchIndividuals. Empty fields
+// are not searched on.
+type SearchIndividualsRequest struct {
+	FirstName string
+	LastName  string
+	Email     string
+	Phone     string
+}
+
+// SearchIndividuals returns the individuals matching every field of the request.
+func (svc *defaultService) SearchIndividuals(ctx context.Context, req SearchIndividualsRequest) ([]Individual, error) {
+	vouslog.GetLogger(ctx).Info("Searching individuals in CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "individual_search")
+	for name, value := range map[string]string{
+		"first_name": req.FirstName,
+		"last_name":  req.LastName,
+		"email":      req.Email,
+		"phone":      req.Phone,
+	} {
+		if value != "" {
+			q.Add(name, value)
+		}
+	}
+	if len(q) == 1 {
+		return nil, errors.New("search individuals: no search fields")
+	}
+
+	individuals := []Individual{}
+	if err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		return ccbxml.EachIndividual(r, func(v *ccbxml.Individual) error {
+			individuals = append(individuals, svc.individualFromRecord(ctx, v))
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	// Labelled once the body is read, since labels may need another call to CCB.
+	for i := range individuals {
+		svc.labelCustomFields(ctx, &individuals[i])
+	}
+	return individuals, nil
+}
+
+// CreateIndividualRequest represents a request to CreateIndividual.
+type CreateIndividualRequest struct {
+	FirstName   string
+	LastName    string
+	Email       string
+	MobilePhone string
+	CampusID    string
+}
+
+// CreateIndividual creates an individual in CCB and returns it.
+func (svc *defaultService) CreateIndividual(ctx context.Context, req CreateIndividualRequest) (*Individual, error) {
+	vouslog.GetLogger(ctx).Info("Creating individual in CCB.")
+
+	if req.FirstName == "" || req.LastName == "" {
+		return nil, errors.New("create individual: first and last name are required")
+	}
+	q := url.Values{}
+	q.Add("srv", "create_individual")
+	q.Add("first_name", req.FirstName)
+	q.Add("last_name", req.LastName)
+	if req.Email != "" {
+		q.Add("email", req.Email)
+	}
+	if req.MobilePhone != "" {
+		q.Add("mobile_phone", req.MobilePhone)
+	}
+	if req.CampusID != "" {
+		q.Add("campus_id", req.CampusID)
+	}
+
+	data, err := svc.postCCB(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	individuals := svc.individualsFromCCB(ctx, data)
+	if len(individuals) == 0 {
+		return nil, errors.New("create individual: no individual returned from CCB")
+	}
+	return &individuals[0], nil
+}
+
+// individualsFromCCB builds the individuals from a response listing individuals,
+// logging any warnings.
+func (svc *defaultService) individualsFromCCB(ctx context.Context, data *ccbxml.Response) []Individual {
+	individuals := []Individual{}
+	if data.Response.Individuals == nil {
+		return individuals
+	}
+	for _, v := range data.Response.Individuals.Individual {
+		if v != nil {
+			individuals = append(individuals, svc.individualFromRecord(ctx, v))
+		}
+	}
+	for i := range individuals {
+		svc.labelCustomFields(ctx, &individuals[i])
+	}
+	return individuals
+}
+
+// individualFromRecord builds the individual from a record of a response listing
+// individuals, logging any warnings. Custom fields are left unlabelled.
+func (svc *defaultService) individualFromRecord(ctx context.Context, v *ccbxml.Individual) Individual {
+	individual, warnings := individualFromCCB(v, svc.config.Location())
+	if len(warnings) > 0 {
+		vouslog.GetLogger(ctx).WithFields(logrus.Fields{
+			"individual_id": v.ID,
+			"warnings":      warnings,
+		}).Warn("Malformed individual from CCB.")
+	}
+	return *individual
+}
@@ -0,0 +1,70 @@
+package ccb
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// IndividualDetails are the details people give about themselves, such as on the
+// website, used to find them in CCB.
+type IndividualDetails struct {
+	FirstName string
+	LastName  string
+	Email     string // Normalized, see NormalizeEmail.
+	Phone     string // Normalized, see NormalizePhone.
+	CampusID  string // Campus to create the individual in.
+}
+
+// MatchOrCreateIndividual returns the individual in CCB matching the details, or
+// creates one if there is none. created is true if the individual was created.
+//
+// Individuals match if they have the same first name, and the same email or phone.
+// Families often share an email or phone, so neither is enough on its own.
+func MatchOrCreateIndividual(ctx context.Context, svc Service, d IndividualDetails) (individual *Individual, created bool, err error) {
+	logger := vouslog.GetLogger(ctx)
+	if d.FirstName == "" || d.LastName == "" {
+		return nil, false, errors.New("first and last name are required")
+	}
+	if d.Email == "" && d.Phone == "" {
+		return nil, false, errors.New("email or phone is required")
+	}
+
+	searches := []SearchIndividualsRequest{}
+	if d.Email != "" {
+		searches = append(searches, SearchIndividualsRequest{Email: d.Email})
+	}
+	if d.Phone != "" {
+		searches = append(searches, SearchIndividualsRequest{Phone: d.Phone})
+	}
+	for _, search := range searches {
+		candidates, err := svc.SearchIndividuals(ctx, search)
+		if err != nil {
+			return nil, false, errors.New("search individuals: " + err.Error())
+		}
+		for i := range candidates {
+			if strings.EqualFold(strings.TrimSpace(candidates[i].FirstName), d.FirstName) {
+				logger.WithField("individual_id", candidates[i].ID).Info("Matched individual in CCB.")
+				return &candidates[i], false, nil
+			}
+		}
+	}
+
+	individual, err = svc.CreateIndividual(ctx, CreateIndividualRequest{
+		FirstName:   d.FirstName,
+		LastName:    d.LastName,
+		Email:       d.Email,
+		MobilePhone: d.Phone,
+		CampusID:    d.CampusID,
+	})
+	if err != nil {
+		return nil, false, errors.New("create individual: " + err.Error())
+	}
+	logger.WithFields(logrus.Fields{
+		"individual_id": individual.ID,
+	}).Info("Created individual in CCB.")
+	return individual, true, nil
+}
@@ -0,0 +1,55 @@
+package ccb
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/metrics"
+)
+
+// rateLimitRemainingHeader is the header CCB uses to report the remaining API quota.
+const rateLimitRemainingHeader = "X-RateLimit-Remaining"
+
+var (
+	ccbCallsTotal = metrics.NewCounterVec(
+		"ccb_calls_total",
+		"Number of calls made to the CCB API, by service.",
+		"srv",
+	)
+	ccbRetriesTotal = metrics.NewCounterVec(
+		"ccb_retries_total",
+		"Number of retried calls to the CCB API, by service.",
+		"srv",
+	)
+	ccbResponsesTotal = metrics.NewCounterVec(
+		"ccb_responses_total",
+		"Number of responses received from the CCB API, by service and status code.",
+		"srv", "status_code",
+	)
+	ccbQuotaRemaining = metrics.NewGaugeVec(
+		"ccb_quota_remaining",
+		"API calls remaining in the current CCB rate limit window, as last reported by CCB.",
+	)
+	ccbDecodeFailuresTotal = metrics.NewCounterVec(
+		"ccb_xml_decode_failures_total",
+		"Number of CCB responses which failed to decode as XML, by service.",
+		"srv",
+	)
+)
+
+// srvName returns the CCB service name the request is calling.
+func srvName(req *http.Request) string {
+	if srv := req.URL.Query().Get("srv"); srv != "" {
+		return srv
+	}
+	return "unknown"
+}
+
+// observeResponse records the status code and remaining quota from a CCB response.
+func observeResponse(srv string, resp *http.Response) {
+	ccbResponsesTotal.Inc(srv, strconv.Itoa(resp.StatusCode))
+
+	if remaining, err := strconv.ParseFloat(resp.Header.Get(rateLimitRemainingHeader), 64); err == nil {
+		ccbQuotaRemaining.Set(remaining)
+	}
+}
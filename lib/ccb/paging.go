@@ -0,0 +1,68 @@
+package ccb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// DefaultPageSize is the page size used when auto-paging through CCB results.
+const DefaultPageSize = 100
+
+// errStopPaging can be returned from an EachFormResponse or EachGroup callback to
+// stop early without an error.
+var errStopPaging = errors.New("stop paging")
+
+// StopPaging returns the error a callback should return to stop paging early.
+// EachFormResponse and EachGroup then return nil.
+func StopPaging() error {
+	return errStopPaging
+}
+
+// EachFormResponse calls fn for every form response matching req as it is read,
+// fetching page after page from CCB until a page with fewer results than the page
+// size is returned. Paging starts at req.Page, or the first page if not set.
+// If fn returns an error paging stops and the error is returned.
+func EachFormResponse(ctx context.Context, svc Service, req GetFormResponsesRequest, fn func(FormResponse) error) error {
+	return eachPage(&req.Page, &req.PageSize, func() (int, error) {
+		var fnErr error
+		n, err := svc.StreamFormResponses(ctx, req, func(r FormResponse) error {
+			fnErr = fn(r)
+			return fnErr
+		})
+		if fnErr != nil {
+			return n, fnErr
+		}
+		if err != nil {
+			return n, errors.New("get form responses page " + strconv.Itoa(req.Page) + ": " + err.Error())
+		}
+		return n, nil
+	})
+}
+
+// eachPage calls fetch for page after page, starting at *page or the first page,
+// until fetch returns fewer records than *pageSize or an error. fetch reads the
+// page and page size from page and pageSize, which default to the first page and
+// DefaultPageSize. errStopPaging stops paging without an error.
+func eachPage(page, pageSize *int, fetch func() (int, error)) error {
+	if *page < 1 {
+		*page = 1
+	}
+	if *pageSize < 1 {
+		*pageSize = DefaultPageSize
+	}
+
+	for {
+		n, err := fetch()
+		if err == errStopPaging {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if n < *pageSize {
+			return nil
+		}
+		*page++
+	}
+}
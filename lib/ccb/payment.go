@@ -0,0 +1,161 @@
+package ccb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb/ccbxml"
+)
+
+// PaymentStatus is the status of the payment for a form response.
+type PaymentStatus string
+
+// PaymentStatus values.
+const (
+	PaymentStatusPaid     PaymentStatus = "paid"
+	PaymentStatusPartial  PaymentStatus = "partial"
+	PaymentStatusUnpaid   PaymentStatus = "unpaid"
+	PaymentStatusRefunded PaymentStatus = "refunded"
+)
+
+// Money is an amount of money in cents, serialized as a decimal string such as "12.50".
+type Money int64
+
+// ParseMoney parses a decimal amount such as "$1,234.5" into Money.
+func ParseMoney(s string) (Money, error) {
+	s = strings.NewReplacer("$", "", ",", "", " ", "").Replace(strings.TrimSpace(s))
+	if s == "" {
+		return 0, errors.New("empty amount")
+	}
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac := s, ""
+	if i := strings.Index(s, "."); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if len(frac) > 2 {
+		return 0, errors.New("too many decimal places: " + s)
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+	if whole == "" {
+		whole = "0"
+	}
+
+	cents, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid amount: " + s)
+	}
+	if neg {
+		cents = -cents
+	}
+	return Money(cents), nil
+}
+
+// String returns the amount as a decimal string.
+func (m Money) String() string {
+	sign := ""
+	if m < 0 {
+		sign, m = "-", -m
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, m/100, m%100)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Payment is the payment made with a form response, such as an event registration.
+type Payment struct {
+	Amount        Money         `json:"amount"`  // Total amount due for the registration.
+	Paid          Money         `json:"paid"`    // Amount paid so far.
+	Balance       Money         `json:"balance"` // Amount still owed.
+	Status        PaymentStatus `json:"status"`
+	TransactionID string        `json:"transaction_id,omitempty"`
+	Coupon        string        `json:"coupon,omitempty"`
+	Discount      Money         `json:"discount,omitempty"`
+}
+
+// paymentFromCCB builds the Payment from its CCB representation. Returns nil if the
+// form response has no payment.
+func paymentFromCCB(p *ccbxml.PaymentInfo) (*Payment, []string) {
+	if p == nil || len(p.Fields) == 0 {
+		return nil, nil
+	}
+
+	var warnings []string
+	money := func(name string, aliases ...string) (Money, bool) {
+		raw := p.Field(aliases...)
+		if raw == "" {
+			return 0, false
+		}
+		m, err := ParseMoney(raw)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("invalid payment %s %q", name, raw))
+			return 0, false
+		}
+		return m, true
+	}
+
+	pay := &Payment{
+		TransactionID: p.Field("transaction_id", "transaction", "payment_id"),
+		Coupon:        p.Field("coupon", "coupon_code"),
+	}
+	amount, hasAmount := money("amount", "amount", "total", "amount_due", "cost")
+	paid, hasPaid := money("paid", "amount_paid", "paid", "payment_amount")
+	balance, hasBalance := money("balance", "balance", "balance_due", "remaining")
+	pay.Discount, _ = money("discount", "discount", "coupon_amount")
+
+	if !hasAmount && !hasPaid && !hasBalance && pay.TransactionID == "" {
+		return nil, warnings
+	}
+
+	// Fill in whichever of the amounts CCB left out.
+	switch {
+	case hasAmount && hasPaid && !hasBalance:
+		balance = amount - paid
+	case hasAmount && hasBalance && !hasPaid:
+		paid = amount - balance
+	case hasPaid && hasBalance && !hasAmount:
+		amount = paid + balance
+	case hasAmount && !hasPaid && !hasBalance:
+		// Without a transaction nothing has been paid.
+		if pay.TransactionID != "" {
+			paid = amount
+		} else {
+			balance = amount
+		}
+	}
+	pay.Amount, pay.Paid, pay.Balance = amount, paid, balance
+
+	switch status := strings.ToLower(p.Field("status", "payment_status")); {
+	case strings.Contains(status, "refund"):
+		pay.Status = PaymentStatusRefunded
+	case balance <= 0:
+		pay.Status = PaymentStatusPaid
+	case paid > 0:
+		pay.Status = PaymentStatusPartial
+	default:
+		pay.Status = PaymentStatusUnpaid
+	}
+
+	return pay, warnings
+}
@@ -0,0 +1,266 @@
+package ccb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb/ccbxml"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// QueuesConfig configures the process queues known to the API by name, so forms
+// and the website can route people into queues without knowing their CCB ids.
+type QueuesConfig struct {
+	// QueueIDs maps a queue name to the CCB queue id, e.g.
+	// first_time_guest:12,prayer_request:15.
+	QueueIDs map[string]string `envconfig:"CCB_QUEUES"`
+}
+
+// QueueID returns the id of the queue with the configured name, or the name itself
+// if it is a CCB queue id.
+func (cfg QueuesConfig) QueueID(name string) (string, bool) {
+	if id, ok := cfg.QueueIDs[name]; ok {
+		return id, true
+	}
+	if _, err := strconv.Atoi(name); err == nil {
+		return name, true
+	}
+	return "", false
+}
+
+// QueueStatus is the status of an individual in a queue.
+type QueueStatus string
+
+// The statuses of individuals in queues.
+const (
+	QueueNotStarted QueueStatus = "not_started"
+	QueueInProgress QueueStatus = "in_progress"
+	QueueDone       QueueStatus = "done"
+)
+
+// Process is a CCB process, which is a series of queues people move through, such
+// as following up with first-time guests.
+type Process struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	CampusID    string `json:"campus_id,omitempty"`
+	Campus      string `json:"campus,omitempty"` // Campus slug.
+	Manager     string `json:"manager,omitempty"`
+}
+
+// Queue is a step of a process, holding the individuals at that step.
+type Queue struct {
+	ID          string `json:"id"`
+	ProcessID   string `json:"process_id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Manager     string `json:"manager,omitempty"`
+}
+
+// QueueIndividual is an individual in a queue.
+type QueueIndividual struct {
+	ID      string      `json:"id"`
+	Name    string      `json:"name,omitempty"`
+	Status  QueueStatus `json:"status,omitempty"`
+	Manager string      `json:"manager,omitempty"`
+	Note    string      `json:"note,omitempty"`
+	DueDate *time.Time  `json:"due_date,omitempty"`
+	Added   *time.Time  `json:"added,omitempty"`
+}
+
+// AddIndividualToQueueRequest is a request to add an individual to a queue.
+type AddIndividualToQueueRequest struct {
+	QueueID      string
+	IndividualID string
+	ManagerID    string     // Individual responsible for following up, optional.
+	Note         string     // Optional.
+	DueDate      *time.Time // Optional.
+}
+
+// ManageQueueIndividualRequest is a request to update an individual in a queue.
+// Empty fields are left unchanged.
+type ManageQueueIndividualRequest struct {
+	QueueID      string
+	IndividualID string
+	Status       QueueStatus
+	ManagerID    string
+	Note         string
+	DueDate      *time.Time
+}
+
+// ListProcesses returns every process.
+func (svc *defaultService) ListProcesses(ctx context.Context) ([]Process, error) {
+	vouslog.GetLogger(ctx).Info("Listing processes from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "process_list")
+
+	processes := []Process{}
+	if err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		return ccbxml.EachProcess(r, func(v *ccbxml.Process) error {
+			p := Process{
+				ID:          v.ID,
+				Name:        strings.TrimSpace(v.Name),
+				Description: strings.TrimSpace(v.Description),
+				CampusID:    v.Campus.ID,
+				Manager:     strings.TrimSpace(v.Manager.Name),
+			}
+			if v.Campus.ID != "" {
+				p.Campus = svc.campusSlug(v.Campus.ID, v.Campus.Name)
+			}
+			processes = append(processes, p)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return processes, nil
+}
+
+// ListQueues returns the queues of the process, or ErrNotFound.
+func (svc *defaultService) ListQueues(ctx context.Context, processID string) ([]Queue, error) {
+	vouslog.GetLogger(ctx).WithField("process_id", processID).Info("Listing queues from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "queue_list")
+	q.Add("id", processID)
+
+	queues := []Queue{}
+	var data *ccbxml.Response
+	if err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		var err error
+		data, err = ccbxml.EachQueue(r, func(v *ccbxml.Queue) error {
+			queues = append(queues, Queue{
+				ID:          v.ID,
+				ProcessID:   processID,
+				Name:        strings.TrimSpace(v.Name),
+				Description: strings.TrimSpace(v.Description),
+				Manager:     strings.TrimSpace(v.Manager.Name),
+			})
+			return nil
+		})
+		return data, err
+	}); err != nil {
+		return nil, err
+	}
+	if data.Response.Queues == nil {
+		return nil, ErrNotFound
+	}
+	return queues, nil
+}
+
+// ListQueueIndividuals returns the individuals in the queue, or ErrNotFound.
+func (svc *defaultService) ListQueueIndividuals(ctx context.Context, queueID string) ([]QueueIndividual, error) {
+	logger := vouslog.GetLogger(ctx).WithField("queue_id", queueID)
+	logger.Info("Listing queue individuals from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "queue_individuals")
+	q.Add("id", queueID)
+
+	// The response has the one queue, so its individuals are built as they're read.
+	loc := svc.config.Location()
+	found := false
+	individuals := []QueueIndividual{}
+	var warnings []string
+	if err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		return ccbxml.EachQueueIndividual(r, func(string) error {
+			found = true
+			return nil
+		}, func(v *ccbxml.QueueIndividual) error {
+			qi := QueueIndividual{
+				ID:      v.ID,
+				Name:    strings.TrimSpace(v.Name),
+				Status:  QueueStatus(strings.TrimSpace(v.Status)),
+				Manager: strings.TrimSpace(v.Manager.Name),
+				Note:    strings.TrimSpace(v.Note),
+			}
+			var err error
+			if qi.DueDate, err = parseDay(v.DueDate, loc); err != nil {
+				warnings = append(warnings, fmt.Sprintf("invalid due date %q of individual %s", v.DueDate, v.ID))
+			}
+			if qi.Added, err = parseTimestamp(v.DateAdded, loc); err != nil {
+				warnings = append(warnings, fmt.Sprintf("invalid date added %q of individual %s", v.DateAdded, v.ID))
+			}
+			individuals = append(individuals, qi)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrNotFound
+	}
+	if len(warnings) > 0 {
+		logger.WithField("warnings", warnings).Warn("Malformed queue individuals from CCB.")
+	}
+	return individuals, nil
+}
+
+// AddIndividualToQueue adds the individual to the queue.
+func (svc *defaultService) AddIndividualToQueue(ctx context.Context, req AddIndividualToQueueRequest) error {
+	if req.QueueID == "" || req.IndividualID == "" {
+		return errors.New("queue id and individual id are required")
+	}
+	vouslog.GetLogger(ctx).WithFields(logrus.Fields{
+		"queue_id":      req.QueueID,
+		"individual_id": req.IndividualID,
+	}).Info("Adding individual to queue in CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "add_individual_to_queue")
+	q.Add("queue_id", req.QueueID)
+	q.Add("individual_id", req.IndividualID)
+	if req.ManagerID != "" {
+		q.Add("manager_id", req.ManagerID)
+	}
+	if req.Note != "" {
+		q.Add("note", req.Note)
+	}
+	if req.DueDate != nil {
+		q.Add("due_date", req.DueDate.In(svc.config.Location()).Format(dateLayout))
+	}
+
+	_, err := svc.postCCB(ctx, q)
+	return err
+}
+
+// ManageQueueIndividual updates the individual in the queue.
+func (svc *defaultService) ManageQueueIndividual(ctx context.Context, req ManageQueueIndividualRequest) error {
+	if req.QueueID == "" || req.IndividualID == "" {
+		return errors.New("queue id and individual id are required")
+	}
+	vouslog.GetLogger(ctx).WithFields(logrus.Fields{
+		"queue_id":      req.QueueID,
+		"individual_id": req.IndividualID,
+		"status":        req.Status,
+	}).Info("Managing queue individual in CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "manage_queue_individual")
+	q.Add("queue_id", req.QueueID)
+	q.Add("individual_id", req.IndividualID)
+	if req.Status != "" {
+		q.Add("status", string(req.Status))
+	}
+	if req.ManagerID != "" {
+		q.Add("manager_id", req.ManagerID)
+	}
+	if req.Note != "" {
+		q.Add("note", req.Note)
+	}
+	if req.DueDate != nil {
+		q.Add("due_date", req.DueDate.In(svc.config.Location()).Format(dateLayout))
+	}
+
+	_, err := svc.postCCB(ctx, q)
+	return err
+}
@@ -0,0 +1,109 @@
+package ccb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb/ccbxml"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+)
+
+// SignificantEventType is a type of significant event, such as baptism or
+// completing a Growth Track step.
+type SignificantEventType struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Order int    `json:"order"`
+}
+
+// SignificantEvent is a significant event in the life of an individual.
+type SignificantEvent struct {
+	TypeID string     `json:"type_id,omitempty"`
+	Name   string     `json:"name"` // Name of the type.
+	Date   *time.Time `json:"date,omitempty"`
+	Notes  string     `json:"notes,omitempty"`
+}
+
+// ListSignificantEventTypes returns every type of significant event, in the order
+// they are listed in CCB.
+func (svc *defaultService) ListSignificantEventTypes(ctx context.Context) ([]SignificantEventType, error) {
+	vouslog.GetLogger(ctx).Info("Listing significant event types from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "significant_event_list")
+
+	types := []SignificantEventType{}
+	if err := svc.streamCCB(ctx, q, func(r io.Reader) (*ccbxml.Response, error) {
+		return ccbxml.EachItem(r, func(v *ccbxml.Item) error {
+			if v.ID == "" {
+				return nil
+			}
+			order, _ := strconv.Atoi(strings.TrimSpace(v.Order)) // Unordered types sort first.
+			types = append(types, SignificantEventType{
+				ID:    v.ID,
+				Name:  strings.TrimSpace(v.Name),
+				Order: order,
+			})
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(types, func(i, j int) bool {
+		return types[i].Order < types[j].Order
+	})
+	return types, nil
+}
+
+// GetIndividualSignificantEvents returns the significant events of the individual,
+// oldest first, or ErrNotFound.
+func (svc *defaultService) GetIndividualSignificantEvents(ctx context.Context, individualID string) ([]SignificantEvent, error) {
+	logger := vouslog.GetLogger(ctx).WithField("individual_id", individualID)
+	logger.Info("Getting individual significant events from CCB.")
+
+	q := url.Values{}
+	q.Add("srv", "individual_significant_events")
+	q.Add("id", individualID)
+
+	data, err := svc.callCCB(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if data.Response.Individuals == nil || len(data.Response.Individuals.Individual) == 0 || data.Response.Individuals.Individual[0] == nil {
+		return nil, ErrNotFound
+	}
+
+	loc := svc.config.Location()
+	events := []SignificantEvent{}
+	var warnings []string
+	for _, v := range data.Response.Individuals.Individual[0].SignificantEvents {
+		if v == nil {
+			continue
+		}
+		e := SignificantEvent{
+			TypeID: v.ID,
+			Name:   strings.TrimSpace(v.Name),
+			Notes:  strings.TrimSpace(v.Notes),
+		}
+		if e.Date, err = parseDay(v.Date, loc); err != nil {
+			warnings = append(warnings, fmt.Sprintf("invalid date %q of %q", v.Date, e.Name))
+		}
+		events = append(events, e)
+	}
+	if len(warnings) > 0 {
+		logger.WithField("warnings", warnings).Warn("Malformed significant events from CCB.")
+	}
+
+	// Events without a date sort last.
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i].Date, events[j].Date
+		return a != nil && (b == nil || a.Before(*b))
+	})
+	return events, nil
+}
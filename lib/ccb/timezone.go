@@ -0,0 +1,51 @@
+package ccb
+
+import (
+	"errors"
+	"time"
+)
+
+// Location is a time zone which can be configured by name, e.g. America/New_York.
+type Location struct {
+	*time.Location
+}
+
+// Decode implements envconfig.Decoder.
+func (l *Location) Decode(value string) error {
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return errors.New("load time zone: " + err.Error())
+	}
+	l.Location = loc
+	return nil
+}
+
+// Location returns the time zone of the church, which CCB timestamps are in.
+// Defaults to UTC if not configured.
+func (cfg Config) Location() *time.Location {
+	if cfg.Timezone.Location == nil {
+		return time.UTC
+	}
+	return cfg.Timezone.Location
+}
+
+// parseTimestamp parses a CCB timestamp in the time zone. Returns nil if empty.
+func parseTimestamp(s string, loc *time.Location) (*time.Time, error) {
+	return parseInLocation(timestampLayout, s, loc)
+}
+
+// parseDay parses a CCB date as midnight in the time zone. Returns nil if empty.
+func parseDay(s string, loc *time.Location) (*time.Time, error) {
+	return parseInLocation(dateLayout, s, loc)
+}
+
+func parseInLocation(layout, s string, loc *time.Location) (*time.Time, error) {
+	if s == "" || s == "0000-00-00" || s == "0000-00-00 00:00:00" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation(layout, s, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
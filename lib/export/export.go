@@ -0,0 +1,229 @@
+// Package export writes CCB form responses as CSV or NDJSON. It pages through CCB
+// once, writing NDJSON records as they arrive and spooling CSV rows to a temp file
+// until the columns are known, so it can be used both by the API and by scheduled
+// report jobs without holding the export in memory.
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb"
+)
+
+// Format is an export file format.
+type Format string
+
+// Supported export formats.
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+// ParseFormat parses the format name, defaulting to CSV if empty.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatNDJSON:
+		return FormatNDJSON, nil
+	}
+	return "", errors.New("unsupported export format: " + s)
+}
+
+// ContentType returns the MIME type for the format.
+func (f Format) ContentType() string {
+	if f == FormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Fixed columns written before the profile and answer columns in CSV exports.
+var fixedColumns = []string{"id", "form_id", "campus", "individual_id", "individual_name", "created", "modified"}
+
+// FormResponses writes every form response matching the requests to w in the
+// format, one request after the other. Several requests are used to export the
+// same form type across campuses. Paging starts from the first page regardless of
+// the request Page.
+//
+// CSV exports need the union of all profile fields and questions for the header,
+// so rows are spooled to a temp file while the columns are collected and only
+// written once every response has been read.
+func FormResponses(ctx context.Context, svc ccb.Service, reqs []ccb.GetFormResponsesRequest, format Format, w io.Writer) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(ctx, svc, reqs, w)
+	case FormatNDJSON:
+		return writeNDJSON(ctx, svc, reqs, w)
+	}
+	return errors.New("unsupported export format: " + string(format))
+}
+
+// each calls fn for every form response matching the requests.
+func each(ctx context.Context, svc ccb.Service, reqs []ccb.GetFormResponsesRequest, fn func(ccb.FormResponse) error) error {
+	for _, req := range reqs {
+		req.Page = 1
+		if err := ccb.EachFormResponse(ctx, svc, req, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeNDJSON(ctx context.Context, svc ccb.Service, reqs []ccb.GetFormResponsesRequest, w io.Writer) error {
+	enc := json.NewEncoder(w) // Encode terminates each value with a newline.
+	return each(ctx, svc, reqs, func(r ccb.FormResponse) error {
+		if err := enc.Encode(r); err != nil {
+			return errors.New("encode form response: " + err.Error())
+		}
+		return nil
+	})
+}
+
+func writeCSV(ctx context.Context, svc ccb.Service, reqs []ccb.GetFormResponsesRequest, w io.Writer) error {
+	spool, err := ioutil.TempFile("", "export-*.ndjson")
+	if err != nil {
+		return errors.New("create spool: " + err.Error())
+	}
+	defer func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}()
+
+	// Read CCB once, so responses changed during the export can't add columns
+	// after the header is written.
+	cols := &columns{profileSeen: map[string]bool{}, answerSeen: map[string]bool{}}
+	buf := bufio.NewWriter(spool)
+	enc := json.NewEncoder(buf)
+	if err := each(ctx, svc, reqs, func(r ccb.FormResponse) error {
+		cols.add(r)
+		return enc.Encode(newSpooledRow(r))
+	}); err != nil {
+		return errors.New("spool rows: " + err.Error())
+	}
+	if err := buf.Flush(); err != nil {
+		return errors.New("spool rows: " + err.Error())
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return errors.New("rewind spool: " + err.Error())
+	}
+
+	cols.profile = sortedKeys(cols.profileSeen)
+	cw := csv.NewWriter(w)
+	if err := cw.Write(cols.header()); err != nil {
+		return errors.New("write header: " + err.Error())
+	}
+	dec := json.NewDecoder(bufio.NewReader(spool))
+	for {
+		var r spooledRow
+		if err := dec.Decode(&r); err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.New("read spool: " + err.Error())
+		}
+		if err := cw.Write(cols.row(r)); err != nil {
+			return errors.New("write rows: " + err.Error())
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// spooledRow is the part of a form response written to CSV, spooled until the
+// columns are known.
+type spooledRow struct {
+	Fixed   []string          `json:"fixed"`
+	Profile map[string]string `json:"profile,omitempty"`
+	Answers map[string]string `json:"answers,omitempty"`
+}
+
+func newSpooledRow(r ccb.FormResponse) spooledRow {
+	var individualID, individualName string
+	if r.Individual != nil {
+		individualID, individualName = r.Individual.ID, r.Individual.Name
+	}
+	return spooledRow{
+		Fixed:   []string{r.ID, r.FormID, r.Campus, individualID, individualName, formatTime(r.Created), formatTime(r.Modified)},
+		Profile: r.ProfileInfo,
+		Answers: r.Answers,
+	}
+}
+
+// columns holds the profile fields and questions seen across all responses.
+// Profile fields are sorted by name, and questions are in the order first seen.
+type columns struct {
+	profile     []string // Set from profileSeen once every response is added.
+	profileSeen map[string]bool
+	answerSeen  map[string]bool
+	answers     []string
+}
+
+// add adds the profile fields and questions of the response not seen yet.
+func (c *columns) add(r ccb.FormResponse) {
+	for k := range r.ProfileInfo {
+		c.profileSeen[k] = true
+	}
+	for _, a := range r.AnswerList {
+		if !c.answerSeen[a.Question] {
+			c.answerSeen[a.Question] = true
+			c.answers = append(c.answers, a.Question)
+		}
+	}
+}
+
+// header returns the CSV header. Questions with the same name as a profile field
+// are suffixed so every column is unique.
+func (c *columns) header() []string {
+	taken := map[string]bool{}
+	header := make([]string, 0, len(fixedColumns)+len(c.profile)+len(c.answers))
+	for _, name := range append(append([]string{}, fixedColumns...), c.profile...) {
+		taken[name] = true
+		header = append(header, name)
+	}
+	for _, name := range c.answers {
+		if taken[name] {
+			name += " (answer)"
+		}
+		header = append(header, name)
+	}
+	return header
+}
+
+func (c *columns) row(r spooledRow) []string {
+	row := make([]string, 0, len(r.Fixed)+len(c.profile)+len(c.answers))
+	row = append(row, r.Fixed...)
+	for _, k := range c.profile {
+		row = append(row, r.Profile[k])
+	}
+	for _, k := range c.answers {
+		row = append(row, r.Answers[k])
+	}
+	return row
+}
+
+// formatTime formats the timestamp as RFC3339, or returns an empty string if nil.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
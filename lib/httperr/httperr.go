@@ -0,0 +1,35 @@
+// Package httperr writes API errors in a consistent JSON format:
+//
+//	{"error": {"status": 400, "message": "Invalid form name.", "correlation_id": "..."}}
+package httperr
+
+import (
+	"github.com/kataras/iris/v12/context"
+)
+
+// correlationIDHeader is the response header set by the logging middleware.
+const correlationIDHeader = "Correlation-Id"
+
+// Response is the JSON body written for errors.
+type Response struct {
+	Error Body `json:"error"`
+}
+
+// Body describes the error.
+type Body struct {
+	Status        int    `json:"status"`
+	Message       string `json:"message"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// Write writes the error as JSON with the status code.
+func Write(ctx context.Context, statusCode int, message string) {
+	ctx.StatusCode(statusCode)
+	ctx.JSON(Response{
+		Error: Body{
+			Status:        statusCode,
+			Message:       message,
+			CorrelationID: ctx.ResponseWriter().Header().Get(correlationIDHeader),
+		},
+	})
+}
@@ -0,0 +1,137 @@
+// Package ical writes calendars in the iCalendar format of RFC 5545, which
+// calendar apps can subscribe to.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of iCalendar files.
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLineLength is the longest a content line can be in octets, not counting the
+// line break, before it must be folded.
+const maxLineLength = 75
+
+// utcLayout formats times as UTC date-times, so no time zone definitions are needed.
+const utcLayout = "20060102T150405Z"
+
+// Calendar is an iCalendar with events.
+type Calendar struct {
+	ProdID string    // Identifies the product that created the calendar.
+	Name   string    // Shown by calendar apps for subscribed calendars.
+	Stamp  time.Time // When the calendar was created.
+	Events []Event
+}
+
+// Event is an event, which may recur.
+type Event struct {
+	UID         string // Globally unique, and the same every time the calendar is written.
+	Start       time.Time
+	End         time.Time   // Of the first occurrence. Ignored if not after Start.
+	RDates      []time.Time // Starts of later occurrences, which last as long as the first.
+	Summary     string
+	Description string
+	Location    string
+	Categories  []string
+}
+
+// Write writes the calendar to w.
+func Write(w io.Writer, cal Calendar) error {
+	b := bufio.NewWriter(w)
+	l := &lineWriter{w: b}
+
+	l.line("BEGIN:VCALENDAR")
+	l.line("VERSION:2.0")
+	l.line("PRODID:" + cal.ProdID)
+	l.line("CALSCALE:GREGORIAN")
+	l.line("METHOD:PUBLISH")
+	if cal.Name != "" {
+		l.line("X-WR-CALNAME:" + escape(cal.Name))
+	}
+	for _, e := range cal.Events {
+		l.line("BEGIN:VEVENT")
+		l.line("UID:" + escape(e.UID))
+		l.line("DTSTAMP:" + formatTime(cal.Stamp))
+		l.line("DTSTART:" + formatTime(e.Start))
+		if e.End.After(e.Start) {
+			l.line("DTEND:" + formatTime(e.End))
+		}
+		if len(e.RDates) > 0 {
+			dates := make([]string, len(e.RDates))
+			for i, d := range e.RDates {
+				dates[i] = formatTime(d)
+			}
+			l.line("RDATE:" + strings.Join(dates, ","))
+		}
+		l.line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			l.line("DESCRIPTION:" + escape(e.Description))
+		}
+		if e.Location != "" {
+			l.line("LOCATION:" + escape(e.Location))
+		}
+		if len(e.Categories) > 0 {
+			categories := make([]string, len(e.Categories))
+			for i, c := range e.Categories {
+				categories[i] = escape(c)
+			}
+			l.line("CATEGORIES:" + strings.Join(categories, ","))
+		}
+		l.line("END:VEVENT")
+	}
+	l.line("END:VCALENDAR")
+
+	if l.err != nil {
+		return l.err
+	}
+	return b.Flush()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(utcLayout)
+}
+
+// escape escapes a text value.
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// lineWriter writes content lines, folding long lines, and keeps the first error.
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes a content line followed by CRLF, folding it so no line is longer
+// than maxLineLength octets. Lines are only folded between characters, so UTF-8
+// sequences are not split.
+func (l *lineWriter) line(s string) {
+	if l.err != nil {
+		return
+	}
+	limit := maxLineLength
+	for len(s) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(s[i]) {
+			i--
+		}
+		if _, l.err = l.w.WriteString(s[:i] + "\r\n "); l.err != nil {
+			return
+		}
+		s = s[i:]
+		limit = maxLineLength - 1 // Continuation lines start with a space.
+	}
+	_, l.err = l.w.WriteString(s + "\r\n")
+}
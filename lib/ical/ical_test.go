@@ -0,0 +1,77 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// writeLine returns s written as a content line.
+func writeLine(t *testing.T, s string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	l := &lineWriter{w: bufio.NewWriter(&buf)}
+	l.line(s)
+	if l.err != nil {
+		t.Fatal(l.err)
+	}
+	if err := l.w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+// unfold joins folded lines as calendar apps do.
+func unfold(s string) string {
+	return strings.Replace(s, "\r\n ", "", -1)
+}
+
+func TestLineFolding(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:Sunday service"},
+		{"exactly the limit", "SUMMARY:" + strings.Repeat("a", maxLineLength-len("SUMMARY:"))},
+		{"one over the limit", "SUMMARY:" + strings.Repeat("a", maxLineLength-len("SUMMARY:")+1)},
+		{"several lines", "DESCRIPTION:" + strings.Repeat("0123456789", 30)},
+		{"multibyte at the fold", "SUMMARY:" + strings.Repeat("a", maxLineLength-len("SUMMARY:")-1) + strings.Repeat("é", 40)},
+		{"four byte runes", "SUMMARY:" + strings.Repeat("🙏", 60)},
+	}
+	for _, tt := range tests {
+		got := writeLine(t, tt.line)
+		if !strings.HasSuffix(got, "\r\n") {
+			t.Errorf("%s: %q does not end with CRLF", tt.name, got)
+			continue
+		}
+		if unfolded := unfold(strings.TrimSuffix(got, "\r\n")); unfolded != tt.line {
+			t.Errorf("%s: unfolds to %q, want %q", tt.name, unfolded, tt.line)
+		}
+		for i, l := range strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n") {
+			if len(l) > maxLineLength {
+				t.Errorf("%s: line %d is %d octets, want at most %d", tt.name, i, len(l), maxLineLength)
+			}
+			if !utf8.ValidString(l) {
+				t.Errorf("%s: line %d %q splits a UTF-8 sequence", tt.name, i, l)
+			}
+			if i > 0 && !strings.HasPrefix(l, " ") {
+				t.Errorf("%s: continuation line %d %q does not start with a space", tt.name, i, l)
+			}
+		}
+		if len(tt.line) <= maxLineLength && got != tt.line+"\r\n" {
+			t.Errorf("%s: folded %q, want it unchanged", tt.name, got)
+		}
+	}
+}
+
+func TestLineFoldingMultibyteBoundary(t *testing.T) {
+	// "é" is two octets, starting at the last octet allowed on the first line, so
+	// it must move whole to the continuation line.
+	line := strings.Repeat("a", maxLineLength-1) + "é"
+	want := strings.Repeat("a", maxLineLength-1) + "\r\n é\r\n"
+	if got := writeLine(t, line); got != want {
+		t.Errorf("folded %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,333 @@
+// Package metrics implements a small subset of the Prometheus client: counters,
+// gauges and histograms with labels, exposed in the Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the default histogram buckets, in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// collector is implemented by every metric type so the registry can write them out.
+type collector interface {
+	name() string
+	write(w io.Writer) error
+}
+
+// Registry holds a set of metrics to expose.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+// DefaultRegistry is the registry used by the package level constructors and Handler.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates a new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.collectors {
+		if existing.name() == c.name() {
+			panic("metrics: duplicate metric name " + c.name())
+		}
+	}
+	r.collectors = append(r.collectors, c)
+}
+
+// Expose writes all registered metrics to w in the Prometheus text format.
+func (r *Registry) Expose(w io.Writer) error {
+	r.mu.Lock()
+	collectors := make([]collector, len(r.collectors))
+	copy(collectors, r.collectors)
+	r.mu.Unlock()
+
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].name() < collectors[j].name() })
+	for _, c := range collectors {
+		if err := c.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler returns an http.Handler serving the metrics of the registry.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		r.Expose(w) // Best effort, the client may have gone away.
+	})
+}
+
+// Handler returns an http.Handler serving the metrics of the DefaultRegistry.
+func Handler() http.Handler {
+	return DefaultRegistry.Handler()
+}
+
+// vec holds the shared label handling for all metric types.
+type vec struct {
+	metricName string
+	help       string
+	labels     []string
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64  // Counter and gauge value, histogram sum.
+	count       uint64   // Histogram count.
+	buckets     []uint64 // Histogram bucket counts, not cumulative.
+}
+
+func newVec(name, help string, labels []string) vec {
+	return vec{
+		metricName: name,
+		help:       help,
+		labels:     labels,
+		series:     map[string]*series{},
+	}
+}
+
+func (v *vec) name() string {
+	return v.metricName
+}
+
+// get returns the series for the label values, creating it if needed.
+// The caller must hold v.mu.
+func (v *vec) get(labelValues []string, numBuckets int) *series {
+	if len(labelValues) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.metricName, len(v.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{
+			labelValues: append([]string(nil), labelValues...),
+			buckets:     make([]uint64, numBuckets),
+		}
+		v.series[key] = s
+	}
+	return s
+}
+
+// sorted returns a copy of the series ordered by their label values.
+// The caller must hold v.mu.
+func (v *vec) sorted() []series {
+	out := make([]series, 0, len(v.series))
+	for _, s := range v.series {
+		c := *s
+		c.buckets = append([]uint64(nil), s.buckets...)
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return strings.Join(out[i].labelValues, "\xff") < strings.Join(out[j].labelValues, "\xff")
+	})
+	return out
+}
+
+func (v *vec) writeHeader(w io.Writer, typ string) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.metricName, escapeHelp(v.help), v.metricName, typ)
+	return err
+}
+
+// formatLabels renders the label set, with optional extra label pair appended.
+func (v *vec) formatLabels(labelValues []string, extraName, extraValue string) string {
+	if len(labelValues) == 0 && extraName == "" {
+		return ""
+	}
+	pairs := make([]string, 0, len(labelValues)+1)
+	for i, lv := range labelValues {
+		pairs = append(pairs, v.labels[i]+`="`+escapeLabelValue(lv)+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+escapeLabelValue(extraValue)+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	vec
+}
+
+// NewCounterVec creates and registers a new CounterVec in the DefaultRegistry.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{vec: newVec(name, help, labels)}
+	DefaultRegistry.register(c)
+	return c
+}
+
+// Inc increments the counter for the label values by one.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds delta, which must not be negative, to the counter for the label values.
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic("metrics: counter cannot decrease")
+	}
+	c.mu.Lock()
+	c.get(labelValues, 0).value += delta
+	c.mu.Unlock()
+}
+
+func (c *CounterVec) write(w io.Writer) error {
+	c.mu.Lock()
+	all := c.sorted()
+	c.mu.Unlock()
+
+	if err := c.writeHeader(w, "counter"); err != nil {
+		return err
+	}
+	for _, s := range all {
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", c.metricName, c.formatLabels(s.labelValues, "", ""), formatFloat(s.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GaugeVec is a gauge partitioned by labels.
+type GaugeVec struct {
+	vec
+}
+
+// NewGaugeVec creates and registers a new GaugeVec in the DefaultRegistry.
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{vec: newVec(name, help, labels)}
+	DefaultRegistry.register(g)
+	return g
+}
+
+// Set sets the gauge for the label values.
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.mu.Lock()
+	g.get(labelValues, 0).value = value
+	g.mu.Unlock()
+}
+
+// Add adds delta, which may be negative, to the gauge for the label values.
+func (g *GaugeVec) Add(delta float64, labelValues ...string) {
+	g.mu.Lock()
+	g.get(labelValues, 0).value += delta
+	g.mu.Unlock()
+}
+
+func (g *GaugeVec) write(w io.Writer) error {
+	g.mu.Lock()
+	all := g.sorted()
+	g.mu.Unlock()
+
+	if err := g.writeHeader(w, "gauge"); err != nil {
+		return err
+	}
+	for _, s := range all {
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", g.metricName, g.formatLabels(s.labelValues, "", ""), formatFloat(s.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	vec
+	upperBounds []float64
+}
+
+// NewHistogramVec creates and registers a new HistogramVec in the DefaultRegistry.
+// If buckets is empty DefaultBuckets are used.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	upperBounds := append([]float64(nil), buckets...)
+	sort.Float64s(upperBounds)
+
+	h := &HistogramVec{vec: newVec(name, help, labels), upperBounds: upperBounds}
+	DefaultRegistry.register(h)
+	return h
+}
+
+// Observe adds a single observation to the histogram for the label values.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.get(labelValues, len(h.upperBounds))
+	s.value += value
+	s.count++
+	if i := sort.SearchFloat64s(h.upperBounds, value); i < len(h.upperBounds) {
+		s.buckets[i]++
+	}
+}
+
+func (h *HistogramVec) write(w io.Writer) error {
+	h.mu.Lock()
+	all := h.sorted()
+	h.mu.Unlock()
+
+	if err := h.writeHeader(w, "histogram"); err != nil {
+		return err
+	}
+	for _, s := range all {
+		var cumulative uint64
+		for i, upperBound := range h.upperBounds {
+			cumulative += s.buckets[i]
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.formatLabels(s.labelValues, "le", formatFloat(upperBound)), cumulative); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.formatLabels(s.labelValues, "le", "+Inf"), s.count); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s_sum%s %s\n", h.metricName, h.formatLabels(s.labelValues, "", ""), formatFloat(s.value)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s_count%s %d\n", h.metricName, h.formatLabels(s.labelValues, "", ""), s.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExpose(t *testing.T) {
+	r := NewRegistry()
+
+	c := &CounterVec{vec: newVec("requests_total", "Number of requests.\nBy path.", []string{"path"})}
+	r.register(c)
+	c.Inc(`/a"b\c`)
+	c.Add(2, "/")
+
+	g := &GaugeVec{vec: newVec("quota_remaining", "Remaining quota.", nil)}
+	r.register(g)
+	g.Set(42)
+	g.Add(-0.5)
+
+	h := &HistogramVec{vec: newVec("duration_seconds", "Duration.", []string{"route"}), upperBounds: []float64{0.1, 1}}
+	r.register(h)
+	h.Observe(0.05, "/x")
+	h.Observe(0.1, "/x") // On a bound, so counted in its bucket.
+	h.Observe(0.5, "/x")
+	h.Observe(3, "/x") // Only in +Inf.
+
+	var buf bytes.Buffer
+	if err := r.Expose(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP duration_seconds Duration.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{route="/x",le="0.1"} 2
+duration_seconds_bucket{route="/x",le="1"} 3
+duration_seconds_bucket{route="/x",le="+Inf"} 4
+duration_seconds_sum{route="/x"} 3.65
+duration_seconds_count{route="/x"} 4
+# HELP quota_remaining Remaining quota.
+# TYPE quota_remaining gauge
+quota_remaining 41.5
+# HELP requests_total Number of requests.\nBy path.
+# TYPE requests_total counter
+requests_total{path="/"} 2
+requests_total{path="/a\"b\\c"} 1
+`
+	if got := buf.String(); got != want {
+		t.Errorf("exposed:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDuplicateName(t *testing.T) {
+	r := NewRegistry()
+	r.register(&GaugeVec{vec: newVec("up", "Up.", nil)})
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic registering a duplicate name")
+		}
+	}()
+	r.register(&GaugeVec{vec: newVec("up", "Up.", nil)})
+}
@@ -3,9 +3,11 @@ package middleware
 import (
 	stdContext "context"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/kataras/iris/v12/context"
+	"github.com/mruVOUS/ccb-webflow-api/lib/metrics"
 	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
 	"github.com/pborman/uuid"
 	"github.com/sirupsen/logrus"
@@ -19,6 +21,20 @@ const (
 
 const correlationIdHeader = "Correlation-Id"
 
+var (
+	httpRequestsTotal = metrics.NewCounterVec(
+		"http_requests_total",
+		"Number of HTTP requests handled, by route and status code.",
+		"method", "route", "status_code",
+	)
+	httpRequestDuration = metrics.NewHistogramVec(
+		"http_request_duration_seconds",
+		"Latency of HTTP requests, by route and status code.",
+		nil,
+		"method", "route", "status_code",
+	)
+)
+
 type loggingMiddleware struct{}
 
 // NewLogging creates and returns a new request logger middleware.
@@ -67,9 +83,9 @@ func (l *loggingMiddleware) ServeHTTP(ctx context.Context) {
 	logger := logrus.NewEntry(logrus.StandardLogger()).
 		WithFields(logrus.Fields{
 			"correlation_id": correlationID,
-			"method":      ctx.Method(),
-			"path":        ctx.Path(),
-			"ip":          ctx.RemoteAddr(),
+			"method":         ctx.Method(),
+			"path":           ctx.Path(),
+			"ip":             ctx.RemoteAddr(),
 		})
 
 	// Replace the context in iris with the updated context containing
@@ -89,4 +105,32 @@ func (l *loggingMiddleware) ServeHTTP(ctx context.Context) {
 		"status_code": ctx.GetStatusCode(),
 	})
 	logger.Info("Request finished.") // Print first to avoid issues with exotic errors.
+
+	observeRequest(ctx, duration)
+}
+
+// observeRequest records the request in the HTTP metrics. The route template is
+// used rather than the path, and unknown methods are grouped, to keep the number
+// of series bounded.
+func observeRequest(ctx context.Context, duration time.Duration) {
+	route := "unmatched"
+	if r := ctx.GetCurrentRoute(); r != nil {
+		route = r.Path()
+	}
+	method := metricsMethod(ctx.Method())
+	statusCode := strconv.Itoa(ctx.GetStatusCode())
+
+	httpRequestsTotal.Inc(method, route, statusCode)
+	httpRequestDuration.Observe(duration.Seconds(), method, route, statusCode)
+}
+
+// metricsMethod returns the method label of a request, which is OTHER for any
+// method outside the standard set, as clients can send any method.
+func metricsMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return "OTHER"
 }
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+)
+
+type rateLimitMiddleware struct {
+	limit      int
+	window     time.Duration
+	trustProxy bool
+
+	mu      sync.Mutex
+	clients map[string]*rateWindow // By client IP.
+	swept   time.Time              // When expired windows were last removed.
+}
+
+// rateWindow counts the requests of a client since start.
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimit creates and returns a new middleware which allows each client IP
+// limit requests in each window and responds to the rest with a 429.
+// If trustProxy is set the client IP is the last address in X-Forwarded-For, which
+// is the one added by the Heroku router, rather than the address of the connection.
+func NewRateLimit(limit int, window time.Duration, trustProxy bool) context.Handler {
+	m := &rateLimitMiddleware{
+		limit:      limit,
+		window:     window,
+		trustProxy: trustProxy,
+		clients:    map[string]*rateWindow{},
+	}
+	return m.ServeHTTP
+}
+
+// Serve serves the middleware
+func (m *rateLimitMiddleware) ServeHTTP(ctx context.Context) {
+	retryAfter, ok := m.allow(clientIP(ctx.Request(), m.trustProxy), time.Now())
+	if !ok {
+		vouslog.GetLogger(ctx.Request().Context()).Warn("Rate limited.")
+		ctx.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+		httperr.Write(ctx, http.StatusTooManyRequests, "Too many requests, try again later.")
+		ctx.StopExecution()
+		return
+	}
+	ctx.Next()
+}
+
+// allow counts a request from the client at now, returning false and how long
+// until the window ends if the client is over the limit.
+func (m *rateLimitMiddleware) allow(client string, now time.Time) (time.Duration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Forget clients whose window has ended, so the map only holds recent clients.
+	if now.Sub(m.swept) >= m.window {
+		for c, w := range m.clients {
+			if now.Sub(w.start) >= m.window {
+				delete(m.clients, c)
+			}
+		}
+		m.swept = now
+	}
+
+	w := m.clients[client]
+	if w == nil || now.Sub(w.start) >= m.window {
+		w = &rateWindow{start: now}
+		m.clients[client] = w
+	}
+	if w.count >= m.limit {
+		return w.start.Add(m.window).Sub(now), false
+	}
+	w.count++
+	return 0, true
+}
+
+// clientIP returns the IP address of the client making the request.
+func clientIP(req *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if fwd := req.Header.Get("X-Forwarded-For"); fwd != "" {
+			return strings.TrimSpace(fwd[strings.LastIndex(fwd, ",")+1:])
+		}
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	stdContext "context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/sentry"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// maxStackDepth is the max number of frames captured for a crash report.
+const maxStackDepth = 64
+
+// CrashReporter sends reports of recovered panics to an external service.
+type CrashReporter interface {
+	Capture(stdContext.Context, sentry.Event) (string, error)
+}
+
+// JobRunner runs work outside of the request, such that it is waited for before
+// the server exits.
+type JobRunner interface {
+	Go(name string, fn func(stdContext.Context))
+}
+
+type recoverMiddleware struct {
+	reporter CrashReporter
+	jobs     JobRunner
+}
+
+// NewRecover creates and returns a new middleware which recovers from panics in
+// later handlers, logs them with their stack trace and responds with a 500.
+// If reporter is not nil the panic is also sent to it as a job of jobs.
+// It should be used after the logging middleware so the request logger is set.
+func NewRecover(reporter CrashReporter, jobs JobRunner) context.Handler {
+	r := &recoverMiddleware{reporter: reporter, jobs: jobs}
+	return r.ServeHTTP
+}
+
+// Serve serves the middleware
+func (r *recoverMiddleware) ServeHTTP(ctx context.Context) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		// Skip runtime.Callers, this function and the runtime panic.
+		pcs := make([]uintptr, maxStackDepth)
+		pcs = pcs[:runtime.Callers(3, pcs)]
+
+		req := ctx.Request()
+		logger := vouslog.GetLogger(req.Context())
+		logger.WithFields(logrus.Fields{
+			"panic": fmt.Sprint(recovered),
+			"stack": string(debug.Stack()),
+		}).Error("Recovered from panic.")
+
+		if !ctx.IsStopped() {
+			httperr.Write(ctx, http.StatusInternalServerError, "Internal server error.")
+			ctx.StopExecution()
+		}
+
+		if r.reporter != nil {
+			r.report(logger, recovered, pcs, req, ctx.ResponseWriter().Header().Get(correlationIdHeader))
+		}
+	}()
+
+	context.DefaultNext(ctx)
+}
+
+// report sends the panic to the crash reporter in a background job so the response
+// is not held up, and a panic just before shutdown is still reported.
+func (r *recoverMiddleware) report(logger *logrus.Entry, recovered interface{}, pcs []uintptr, req *http.Request, correlationID string) {
+	var frames []runtime.Frame
+	callersFrames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := callersFrames.Next()
+		frames = append(frames, frame)
+		if !more {
+			break
+		}
+	}
+
+	event := sentry.Event{
+		Message: fmt.Sprint(recovered),
+		Type:    fmt.Sprintf("%T", recovered),
+		Frames:  frames,
+		Request: req.Clone(stdContext.Background()),
+		Tags: map[string]string{
+			"correlation_id": correlationID,
+		},
+	}
+
+	r.jobs.Go("crash_report", func(ctx stdContext.Context) {
+		eventID, err := r.reporter.Capture(ctx, event)
+		if err != nil {
+			logger.WithError(err).Error("Failed to send crash report.")
+			return
+		}
+		logger.WithField("crash_report_id", eventID).Info("Sent crash report.")
+	})
+}
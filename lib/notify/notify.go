@@ -0,0 +1,158 @@
+// Package notify sends notifications to staff and volunteers, such as group
+// leaders, through the configured channel: the log, a webhook or email.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// Config holds the configuration of the notification channel.
+type Config struct {
+	Channel    string        `envconfig:"NOTIFY_CHANNEL"     default:"log"` // log, webhook or email.
+	WebhookURL string        `envconfig:"NOTIFY_WEBHOOK_URL"`               // URL messages are posted to as JSON.
+	From       string        `envconfig:"NOTIFY_FROM"`                      // Sender of emails.
+	FallbackTo []string      `envconfig:"NOTIFY_FALLBACK_TO"`               // Staff emailed messages with no recipients, such as for groups without a leader email.
+	Timeout    time.Duration `envconfig:"NOTIFY_TIMEOUT"     default:"10s"`
+
+	SMTPHost     string `envconfig:"SMTP_HOST"`
+	SMTPPort     int    `envconfig:"SMTP_PORT"     default:"587"`
+	SMTPUsername string `envconfig:"SMTP_USERNAME"`
+	SMTPPassword string `envconfig:"SMTP_PASSWORD"`
+}
+
+// Message is a notification.
+type Message struct {
+	To      []string `json:"to"` // Email addresses of the recipients.
+	Subject string   `json:"subject"`
+	Text    string   `json:"text"`
+}
+
+// Notifier sends notifications.
+type Notifier interface {
+	// Notify sends the message.
+	Notify(context.Context, Message) error
+}
+
+// New creates the Notifier for the configured channel.
+func New(cfg Config) (Notifier, error) {
+	switch cfg.Channel {
+	case "", "log":
+		return logNotifier{}, nil
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			return nil, errors.New("NOTIFY_WEBHOOK_URL is required for the webhook channel")
+		}
+		return &webhookNotifier{url: cfg.WebhookURL, client: &http.Client{Timeout: cfg.Timeout}}, nil
+	case "email":
+		if cfg.SMTPHost == "" || cfg.From == "" {
+			return nil, errors.New("SMTP_HOST and NOTIFY_FROM are required for the email channel")
+		}
+		return &emailNotifier{cfg: cfg}, nil
+	}
+	return nil, errors.New("unsupported notification channel: " + cfg.Channel)
+}
+
+// logNotifier logs messages, for development or until a channel is set up. The
+// text is left out, as it can include details submitted by visitors to the website.
+type logNotifier struct{}
+
+func (logNotifier) Notify(ctx context.Context, msg Message) error {
+	vouslog.GetLogger(ctx).WithFields(logrus.Fields{
+		"to":          msg.To,
+		"subject":     msg.Subject,
+		"text_length": len(msg.Text),
+	}).Info("Notification.")
+	return nil
+}
+
+// webhookNotifier posts messages as JSON. The text field makes it compatible with
+// Slack incoming webhooks.
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return errors.New("marshal message: " + err.Error())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("create request: " + err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errors.New("post webhook: " + err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response from webhook: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// emailNotifier sends messages by email over SMTP.
+type emailNotifier struct {
+	cfg Config
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		msg.To = n.cfg.FallbackTo
+	}
+	if len(msg.To) == 0 {
+		return errors.New("no recipients and NOTIFY_FALLBACK_TO is not set")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", headerValue(n.cfg.From))
+	fmt.Fprintf(&buf, "To: %s\r\n", headerValue(strings.Join(msg.To, ", ")))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", headerValue(msg.Subject))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	buf.WriteString(strings.Replace(msg.Text, "\n", "\r\n", -1))
+
+	var auth smtp.Auth
+	if n.cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", n.cfg.SMTPUsername, n.cfg.SMTPPassword, n.cfg.SMTPHost)
+	}
+	addr := net.JoinHostPort(n.cfg.SMTPHost, fmt.Sprint(n.cfg.SMTPPort))
+
+	// smtp.SendMail does not take a context, so give up waiting when it is done.
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, n.cfg.From, msg.To, buf.Bytes())
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return errors.New("send mail: " + err.Error())
+		}
+		return nil
+	case <-ctx.Done():
+		return errors.New("send mail: " + ctx.Err().Error())
+	}
+}
+
+// headerValue removes line breaks from a header value, as values can include
+// details submitted by visitors to the website.
+func headerValue(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
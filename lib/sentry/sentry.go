@@ -0,0 +1,222 @@
+// Package sentry sends crash reports to a Sentry compatible HTTP endpoint using
+// the store API.
+package sentry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const clientName = "ccb-webflow-api/1.0"
+
+// Config holds configuration for sending crash reports.
+type Config struct {
+	DSN         string        `envconfig:"SENTRY_DSN"` // e.g. https://<key>@sentry.io/<project>. Reporting is disabled if empty.
+	Environment string        `envconfig:"SENTRY_ENVIRONMENT" default:"production"`
+	Timeout     time.Duration `envconfig:"SENTRY_TIMEOUT"     default:"5s"` // Timeout for sending a report.
+}
+
+// Client sends events to Sentry.
+type Client struct {
+	storeURL    string
+	auth        string
+	environment string
+	client      *http.Client
+}
+
+// New creates a new Client from the config. It returns nil if no DSN is configured.
+func New(cfg Config) (*Client, error) {
+	if cfg.DSN == "" {
+		return nil, nil
+	}
+
+	dsn, err := url.Parse(cfg.DSN)
+	if err != nil {
+		return nil, errors.New("parse dsn: " + err.Error())
+	}
+	if dsn.User == nil || dsn.User.Username() == "" {
+		return nil, errors.New("dsn is missing the public key")
+	}
+	projectIdx := strings.LastIndex(dsn.Path, "/")
+	if projectIdx < 0 || projectIdx == len(dsn.Path)-1 {
+		return nil, errors.New("dsn is missing the project id")
+	}
+	pathPrefix, projectID := dsn.Path[:projectIdx], dsn.Path[projectIdx+1:]
+
+	auth := fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", clientName, dsn.User.Username())
+	if secret, ok := dsn.User.Password(); ok {
+		auth += ", sentry_secret=" + secret
+	}
+
+	return &Client{
+		storeURL:    fmt.Sprintf("%s://%s%s/api/%s/store/", dsn.Scheme, dsn.Host, pathPrefix, projectID),
+		auth:        auth,
+		environment: cfg.Environment,
+		client:      &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// Event is a crash report to send.
+type Event struct {
+	Message string            // Panic value or error message.
+	Type    string            // Type of the panic value.
+	Frames  []runtime.Frame   // Stack frames, innermost first as returned by runtime.CallersFrames.
+	Request *http.Request     // Optional request being handled.
+	Tags    map[string]string // Optional tags such as the correlation id.
+}
+
+// Capture sends the event to Sentry and returns the event id.
+func (c *Client) Capture(ctx context.Context, e Event) (string, error) {
+	eventID, err := newEventID()
+	if err != nil {
+		return "", errors.New("generate event id: " + err.Error())
+	}
+
+	body, err := json.Marshal(c.payload(eventID, e))
+	if err != nil {
+		return "", errors.New("marshal event: " + err.Error())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.storeURL, bytes.NewReader(body))
+	if err != nil {
+		return "", errors.New("create request: " + err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", c.auth)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", errors.New("do request: " + err.Error())
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body) // Allow connection reuse.
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New("unexpected response from sentry: " + strconv.Itoa(resp.StatusCode))
+	}
+	return eventID, nil
+}
+
+type payload struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Logger      string            `json:"logger"`
+	Environment string            `json:"environment,omitempty"`
+	Message     string            `json:"message"`
+	Exception   *exception        `json:"exception,omitempty"`
+	Request     *request          `json:"request,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+type exception struct {
+	Values []exceptionValue `json:"values"`
+}
+
+type exceptionValue struct {
+	Type       string     `json:"type"`
+	Value      string     `json:"value"`
+	Stacktrace stacktrace `json:"stacktrace"`
+}
+
+type stacktrace struct {
+	Frames []frame `json:"frames"`
+}
+
+type frame struct {
+	Function string `json:"function"`
+	Filename string `json:"filename"`
+	Lineno   int    `json:"lineno"`
+}
+
+type request struct {
+	URL         string            `json:"url"`
+	Method      string            `json:"method"`
+	QueryString string            `json:"query_string,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// sensitiveHeaders are never sent to Sentry.
+var sensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+}
+
+func (c *Client) payload(eventID string, e Event) payload {
+	p := payload{
+		EventID:     eventID,
+		Timestamp:   time.Now().UTC().Format("2006-01-02T15:04:05"),
+		Level:       "fatal",
+		Platform:    "go",
+		Logger:      "panic",
+		Environment: c.environment,
+		Message:     e.Message,
+		Tags:        e.Tags,
+	}
+
+	// Sentry expects the frames ordered from the outermost call to the innermost.
+	frames := make([]frame, 0, len(e.Frames))
+	for i := len(e.Frames) - 1; i >= 0; i-- {
+		frames = append(frames, frame{
+			Function: e.Frames[i].Function,
+			Filename: e.Frames[i].File,
+			Lineno:   e.Frames[i].Line,
+		})
+	}
+	p.Exception = &exception{Values: []exceptionValue{{
+		Type:       e.Type,
+		Value:      e.Message,
+		Stacktrace: stacktrace{Frames: frames},
+	}}}
+
+	if e.Request != nil {
+		headers := map[string]string{}
+		for k := range e.Request.Header {
+			if !sensitiveHeaders[k] {
+				headers[k] = e.Request.Header.Get(k)
+			}
+		}
+		p.Request = &request{
+			URL:         e.Request.URL.Path,
+			Method:      e.Request.Method,
+			QueryString: redactQuery(e.Request.URL.RawQuery),
+			Headers:     headers,
+		}
+	}
+	return p
+}
+
+// redactQuery replaces the values of the query with [Filtered], keeping the
+// parameter names, as values such as profile[email] filters are personal details.
+func redactQuery(rawQuery string) string {
+	q, _ := url.ParseQuery(rawQuery) // Best effort, malformed pairs are left out.
+	for _, v := range q {
+		for i := range v {
+			v[i] = "[Filtered]"
+		}
+	}
+	return q.Encode()
+}
+
+// newEventID returns a random 32 character hex id.
+func newEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
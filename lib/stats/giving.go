@@ -0,0 +1,143 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb"
+)
+
+// batchPostingDays is how many days after the end of the period batches holding
+// its transactions are looked for, as batches are posted some days after the gifts.
+const batchPostingDays = 14
+
+// GivingRequest represents a request to Giving.
+type GivingRequest struct {
+	From   time.Time // Inclusive.
+	To     time.Time // Exclusive.
+	Campus string    // Campus slug, or empty for every campus.
+}
+
+// GivingReport totals the giving between From and To, both inclusive dates. It
+// only holds totals, never who gave.
+type GivingReport struct {
+	From         string      `json:"from"`
+	To           string      `json:"to"`
+	Campus       string      `json:"campus,omitempty"`
+	Total        ccb.Money   `json:"total"`
+	Transactions int         `json:"transactions"`
+	Funds        []FundTotal `json:"funds"` // Largest first.
+	Weeks        []WeekTotal `json:"weeks"`
+}
+
+// FundTotal is the giving to a fund.
+type FundTotal struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Total        ccb.Money `json:"total"`
+	Transactions int       `json:"transactions"`
+}
+
+// WeekTotal is the giving in a week starting on Monday.
+type WeekTotal struct {
+	Start        string    `json:"start"`
+	Total        ccb.Money `json:"total"`
+	Transactions int       `json:"transactions"`
+}
+
+// Giving totals the transactions dated in the requested period by fund and by
+// week. Transactions are matched to the campus of their batch if they have none.
+func Giving(ctx context.Context, svc ccb.Service, req GivingRequest) (*GivingReport, error) {
+	if !req.From.Before(req.To) {
+		return nil, errors.New("from must be before to")
+	}
+
+	funds, err := svc.ListTransactionDetailTypes(ctx)
+	if err != nil {
+		return nil, errors.New("failed to list funds: " + err.Error())
+	}
+	// Batches are posted on or after the day their transactions were given.
+	batches, err := svc.ListBatches(ctx, req.From, req.To.AddDate(0, 0, batchPostingDays))
+	if err != nil {
+		return nil, errors.New("failed to list batches: " + err.Error())
+	}
+
+	report := &GivingReport{
+		From:   req.From.Format(dateLayout),
+		To:     req.To.AddDate(0, 0, -1).Format(dateLayout), // Inclusive, like From.
+		Campus: req.Campus,
+	}
+
+	names := map[string]string{}
+	for _, f := range funds {
+		names[f.ID] = f.Name
+	}
+
+	// Every week is reported, including those without giving.
+	weeks := map[string]*WeekTotal{} // Keyed by start date.
+	var starts []string
+	for t := IntervalWeek.start(req.From); t.Before(req.To); t = IntervalWeek.next(t) {
+		start := t.Format(dateLayout)
+		weeks[start] = &WeekTotal{Start: start}
+		starts = append(starts, start)
+	}
+
+	totals := map[string]*FundTotal{}
+	for _, b := range batches {
+		for _, t := range b.Transactions {
+			if t.Date == nil || t.Date.Before(req.From) || !t.Date.Before(req.To) {
+				continue
+			}
+			campus := t.Campus
+			if campus == "" {
+				campus = b.Campus
+			}
+			if req.Campus != "" && !strings.EqualFold(campus, req.Campus) {
+				continue
+			}
+			date := t.Date.In(req.From.Location()) // Weeks start at midnight in the location of From.
+
+			var amount ccb.Money
+			for _, d := range t.Details {
+				amount += d.Amount
+				f, ok := totals[d.FundID]
+				if !ok {
+					name := d.Fund
+					if n, ok := names[d.FundID]; ok && n != "" {
+						name = n
+					}
+					f = &FundTotal{ID: d.FundID, Name: name}
+					totals[d.FundID] = f
+				}
+				f.Total += d.Amount
+				f.Transactions++
+			}
+
+			report.Total += amount
+			report.Transactions++
+			w := weeks[IntervalWeek.start(date).Format(dateLayout)]
+			w.Total += amount
+			w.Transactions++
+		}
+	}
+
+	report.Funds = make([]FundTotal, 0, len(totals))
+	for _, f := range totals {
+		report.Funds = append(report.Funds, *f)
+	}
+	sort.Slice(report.Funds, func(i, j int) bool {
+		if report.Funds[i].Total != report.Funds[j].Total {
+			return report.Funds[i].Total > report.Funds[j].Total
+		}
+		return report.Funds[i].Name < report.Funds[j].Name
+	})
+
+	report.Weeks = make([]WeekTotal, 0, len(starts))
+	for _, start := range starts {
+		report.Weeks = append(report.Weeks, *weeks[start])
+	}
+	return report, nil
+}
@@ -0,0 +1,221 @@
+// Package stats summarizes CCB form responses: how many were received per
+// interval and campus, and how often each answer was given to multiple choice
+// questions.
+package stats
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb"
+)
+
+// Interval is the length of the periods responses are counted in.
+type Interval string
+
+// Supported intervals. Weeks start on Monday.
+const (
+	IntervalDay   Interval = "day"
+	IntervalWeek  Interval = "week"
+	IntervalMonth Interval = "month"
+)
+
+// ParseInterval parses the interval name, defaulting to a week if empty.
+func ParseInterval(s string) (Interval, error) {
+	switch Interval(s) {
+	case "", IntervalWeek:
+		return IntervalWeek, nil
+	case IntervalDay, IntervalMonth:
+		return Interval(s), nil
+	}
+	return "", errors.New("unsupported interval: " + s)
+}
+
+// start returns the start of the interval containing t.
+func (i Interval) start(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch i {
+	case IntervalWeek:
+		offset := (int(day.Weekday()) + 6) % 7 // Days since Monday.
+		return day.AddDate(0, 0, -offset)
+	case IntervalMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+// next returns the start of the interval after the one starting at t.
+func (i Interval) next(t time.Time) time.Time {
+	switch i {
+	case IntervalWeek:
+		return t.AddDate(0, 0, 7)
+	case IntervalMonth:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// maxChoices is the most distinct answers a question can have to be reported as
+// multiple choice. CCB does not say which questions are multiple choice, and free
+// text questions such as prayer requests would otherwise be reported answer by
+// answer.
+const maxChoices = 20
+
+// Request represents a request to FormResponses.
+type Request struct {
+	FormIDs  []ccb.FormID // Forms to summarize together, e.g. one per campus.
+	From     time.Time    // Inclusive.
+	To       time.Time    // Exclusive.
+	Interval Interval
+}
+
+// Report summarizes the form responses created between From and To, both
+// inclusive dates.
+type Report struct {
+	From      string            `json:"from"`
+	To        string            `json:"to"`
+	Interval  Interval          `json:"interval"`
+	Total     int               `json:"total"`
+	Campuses  map[string]int    `json:"campuses"` // Responses per campus slug.
+	Periods   []Period          `json:"periods"`
+	Questions []QuestionSummary `json:"questions"`
+}
+
+// Period counts the responses created in one interval.
+type Period struct {
+	Start    string         `json:"start"`
+	Total    int            `json:"total"`
+	Campuses map[string]int `json:"campuses"`
+}
+
+// QuestionSummary is the distribution of answers to a multiple choice question.
+type QuestionSummary struct {
+	Question  string        `json:"question"`
+	Responses int           `json:"responses"` // Number of responses answering the question.
+	Answers   []AnswerCount `json:"answers"`   // Most common first.
+}
+
+// AnswerCount is the number of responses which gave an answer.
+type AnswerCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// dateLayout is the layout of dates in the report.
+const dateLayout = "2006-01-02"
+
+// FormResponses pages through the responses of the forms and summarizes those
+// created in the requested period.
+func FormResponses(ctx context.Context, svc ccb.Service, req Request) (*Report, error) {
+	if !req.From.Before(req.To) {
+		return nil, errors.New("from must be before to")
+	}
+
+	report := &Report{
+		From:     req.From.Format(dateLayout),
+		To:       req.To.AddDate(0, 0, -1).Format(dateLayout), // Inclusive, like From.
+		Interval: req.Interval,
+		Campuses: map[string]int{},
+	}
+
+	// Every interval is reported, including those without responses.
+	periods := map[string]*Period{} // Keyed by start date.
+	var starts []string
+	for t := req.Interval.start(req.From); t.Before(req.To); t = req.Interval.next(t) {
+		start := t.Format(dateLayout)
+		periods[start] = &Period{Start: start, Campuses: map[string]int{}}
+		starts = append(starts, start)
+	}
+
+	questions := map[string]*questionTally{}
+	var order []string
+
+	for _, formID := range req.FormIDs {
+		// Responses created in the period were modified in it or after.
+		from := req.From
+		err := ccb.EachFormResponse(ctx, svc, ccb.GetFormResponsesRequest{
+			FormID:        formID,
+			ModifiedSince: &from,
+		}, func(r ccb.FormResponse) error {
+			if r.Created == nil || r.Created.Before(req.From) || !r.Created.Before(req.To) {
+				return nil
+			}
+			created := r.Created.In(req.From.Location()) // Intervals start at midnight in the location of From.
+
+			report.Total++
+			p := periods[req.Interval.start(created).Format(dateLayout)]
+			p.Total++
+			if r.Campus != "" {
+				report.Campuses[r.Campus]++
+				p.Campuses[r.Campus]++
+			}
+
+			for _, a := range r.AnswerList {
+				q, ok := questions[a.Question]
+				if !ok {
+					q = &questionTally{counts: map[string]int{}}
+					questions[a.Question] = q
+					order = append(order, a.Question)
+				}
+				answered := false
+				for _, v := range a.Values {
+					if v = strings.TrimSpace(v); v != "" {
+						q.counts[v]++
+						answered = true
+					}
+				}
+				if answered {
+					q.responses++
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report.Periods = make([]Period, 0, len(starts))
+	for _, start := range starts {
+		report.Periods = append(report.Periods, *periods[start])
+	}
+
+	report.Questions = []QuestionSummary{}
+	for _, question := range order {
+		q := questions[question]
+		if len(q.counts) == 0 || len(q.counts) > maxChoices {
+			continue
+		}
+		report.Questions = append(report.Questions, QuestionSummary{
+			Question:  question,
+			Responses: q.responses,
+			Answers:   q.answers(),
+		})
+	}
+
+	return report, nil
+}
+
+// questionTally counts the answers to a question.
+type questionTally struct {
+	responses int
+	counts    map[string]int
+}
+
+// answers returns the counts, most common first and then by value.
+func (q *questionTally) answers() []AnswerCount {
+	answers := make([]AnswerCount, 0, len(q.counts))
+	for v, n := range q.counts {
+		answers = append(answers, AnswerCount{Value: v, Count: n})
+	}
+	sort.Slice(answers, func(i, j int) bool {
+		if answers[i].Count != answers[j].Count {
+			return answers[i].Count > answers[j].Count
+		}
+		return answers[i].Value < answers[j].Value
+	})
+	return answers
+}
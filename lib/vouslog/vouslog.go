@@ -2,6 +2,7 @@ package vouslog
 
 import (
 	"context"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
 )
@@ -12,20 +13,31 @@ const (
 	contextKey loggerKey = 1
 )
 
+// fallback holds the *logrus.Entry returned for contexts without a logger.
+var fallback atomic.Value
+
+func init() {
+	SetFallback(logrus.NewEntry(logrus.StandardLogger()).WithField("context", "background"))
+}
+
+// SetFallback sets the logger returned by GetLogger for contexts which were not
+// created using WithLogger, such as background jobs started outside of a request.
+func SetFallback(logger *logrus.Entry) {
+	fallback.Store(logger)
+}
+
 // WithLogger will instantiate a new context with the received logger on it.
 func WithLogger(ctx context.Context, logger *logrus.Entry) context.Context {
 	return context.WithValue(ctx, contextKey, logger)
 }
 
 // GetLogger retrieves the current logger from context, if nothing is found it
-// will return the default logger.
+// will return the fallback logger.
 func GetLogger(ctx context.Context) *logrus.Entry {
-	logger := ctx.Value(contextKey)
-
-	if logger == nil {
-		// Logger should have been set in the context using WithLogger.
-		panic("No logger set yet")
+	if ctx != nil {
+		if logger, ok := ctx.Value(contextKey).(*logrus.Entry); ok && logger != nil {
+			return logger
+		}
 	}
-
-	return logger.(*logrus.Entry)
+	return fallback.Load().(*logrus.Entry)
 }
@@ -4,13 +4,16 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
 	iris "github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/middleware/basicauth"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/metrics"
 	"github.com/mruVOUS/ccb-webflow-api/lib/middleware"
+	"github.com/mruVOUS/ccb-webflow-api/lib/sentry"
 	"github.com/sirupsen/logrus"
 	prefixed "github.com/x-cray/logrus-prefixed-formatter"
 )
@@ -18,13 +21,32 @@ import (
 func main() {
 	setupLogging()
 
-	app := iris.New()
+	serverCfg := serverConfig{}
+	envconfig.MustProcess("", &serverCfg)
+
+	sentryCfg := sentry.Config{}
+	envconfig.MustProcess("", &sentryCfg)
+	var crashReporter middleware.CrashReporter
+	sentryClient, err := sentry.New(sentryCfg)
+	if err != nil {
+		logrus.WithError(err).Fatal("Invalid crash reporting config.")
+	}
+	if sentryClient != nil {
+		crashReporter = sentryClient
+	}
+
+	// Load the CCB and notification config now so invalid config, such as an unknown
+	// time zone, stops the server from starting rather than failing requests.
+	getCCBService()
+	getNotifier()
 
-	// Recover middleware recovers from any panics and writes a 500 if there was one.
-	// app.Use(recover.New())
+	app := iris.New()
 
 	app.Use(middleware.NewLogging())
 
+	// Recover middleware recovers from any panics, reports them and writes a 500 if there was one.
+	app.Use(middleware.NewRecover(crashReporter, getJobs()))
+
 	// basic auth set up
 	authConfig := basicauth.Config{
 		Users:   map[string]string{os.Getenv("GO_API_USERNAME"): os.Getenv("GO_API_PASSWORD")},
@@ -33,19 +55,68 @@ func main() {
 	}
 	authentication := basicauth.New(authConfig)
 
+	// Expose Prometheus metrics for scraping, with the admin credentials as they
+	// include routes, CCB quota and error rates.
+	app.Get("/metrics", authentication, iris.FromStd(metrics.Handler()))
+
 	// redirect all requests to authenticated routes
 	app.Get("/", func(ctx iris.Context) { ctx.Redirect("/admin") })
 
 	// set up authenticated routes
 	needAuth := app.Party("/admin", authentication)
 	needAuth.Get("/whois", getPerson)
+	needAuth.Get("/campuses", campusesGet)
 	needAuth.Get("/form_responses/{type: string}", formResponsesGet)
+	needAuth.Get("/form_responses/{type: string}/export", formResponsesExport)
+	needAuth.Get("/forms/{slug: string}/stats", formStatsGet)
+	needAuth.Get("/groups", groupsGet)
+	needAuth.Get("/groups/{id: string}", groupGet)
+	needAuth.Get("/groups/{id: string}/participants", groupParticipantsGet)
+	needAuth.Post("/groups/{id: string}/participants", groupParticipantAdd)
+	needAuth.Delete("/groups/{id: string}/participants/{individual_id: string}", groupParticipantRemove)
+	needAuth.Get("/events/{id: string}", eventGet)
+	needAuth.Get("/attendance", attendanceGet)
+	needAuth.Post("/attendance", attendancePost)
+	needAuth.Get("/processes", processesGet)
+	needAuth.Get("/processes/{id: string}/queues", processQueuesGet)
+	needAuth.Get("/queues/{queue: string}/individuals", queueIndividualsGet)
+	needAuth.Post("/queues/{queue: string}/individuals", queueIndividualAdd)
+	needAuth.Put("/queues/{queue: string}/individuals/{individual_id: string}", queueIndividualUpdate)
+	needAuth.Get("/significant_events", significantEventTypesGet)
+	needAuth.Get("/individuals/{id: string}", individualGet)
+	needAuth.Get("/individuals/{id: string}/significant_events", individualSignificantEventsGet)
+	needAuth.Get("/families/{id: string}", familyGet)
+	needAuth.Get("/custom_fields", customFieldLabelsGet)
+
+	// set up finance routes, which the admin user cannot access. They are left out
+	// unless a finance user is configured.
+	if financeUser := os.Getenv("FINANCE_USERNAME"); financeUser != "" {
+		financeAuth := basicauth.New(basicauth.Config{
+			Users:   map[string]string{financeUser: os.Getenv("FINANCE_PASSWORD")},
+			Realm:   "Finance",
+			Expires: time.Duration(30) * time.Minute,
+		})
+		finance := app.Party("/admin/giving", financeAuth)
+		finance.Get("/summary", givingSummaryGet)
+	}
 
-	portNum := 8080
-	logrus.WithField("port", portNum).Info("Starting server.")
+	// set up public routes, used by the website
+	public := app.Party("/public", allowCORS(os.Getenv("PUBLIC_CORS_ORIGIN"))).AllowMethods(iris.MethodOptions)
+	public.Get("/groups", publicGroupsGet)
+	// Joining a group writes to CCB, so it is left out unless a CAPTCHA is configured,
+	// and is rate limited by client IP. Without TLS the server is behind the Heroku
+	// router, which adds the client IP to X-Forwarded-For.
+	if getCaptcha() != nil {
+		joinCfg := getGroupJoinConfig()
+		public.Post("/groups/{id: string}/join", middleware.NewRateLimit(joinCfg.RateLimit, joinCfg.RateWindow, serverCfg.tlsMode() == "none"), publicGroupJoin)
+	}
+	public.Get("/events", publicEventsGet)
+	public.Get("/events.ics", publicEventsICS)
 
 	// start API
-	app.Run(iris.Addr(":" + strconv.Itoa(portNum)))
+	if err := runServer(app, serverCfg, getJobs()); err != nil {
+		logrus.WithError(err).Fatal("Server failed.")
+	}
 }
 
 // TODO: Move this into a separate file.
@@ -55,8 +126,7 @@ func getPerson(ctx iris.Context) {
 
 	// if no name given, return error
 	if name == "" {
-		ctx.StatusCode(http.StatusBadRequest)
-		ctx.WriteString("Error: No name provided")
+		httperr.Write(ctx, http.StatusBadRequest, "No name provided.")
 		return
 	}
 
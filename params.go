@@ -0,0 +1,18 @@
+package main
+
+import (
+	"strings"
+
+	iris "github.com/kataras/iris/v12"
+)
+
+// hasExpand returns true if the comma separated "expand" query parameter includes
+// the name, e.g. ?expand=individual,groups.
+func hasExpand(ctx iris.Context, name string) bool {
+	for _, v := range strings.Split(ctx.URLParam("expand"), ",") {
+		if strings.TrimSpace(v) == name {
+			return true
+		}
+	}
+	return false
+}
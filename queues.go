@@ -0,0 +1,210 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	iris "github.com/kataras/iris/v12"
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+	"github.com/sirupsen/logrus"
+)
+
+// processesGet handles the GET route for processes.
+func processesGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+
+	processes, err := getCCBService().ListProcesses(ctx.Request().Context())
+	if err != nil {
+		logger.WithError(err).Error("Failed to list processes.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to list processes.")
+		return
+	}
+
+	writeJSON(ctx, map[string]interface{}{"processes": processes})
+}
+
+// processQueuesGet handles the GET route for the queues of a process.
+func processQueuesGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	id := ctx.Params().Get("id")
+
+	queues, err := getCCBService().ListQueues(ctx.Request().Context(), id)
+	if err == ccb.ErrNotFound {
+		httperr.Write(ctx, http.StatusNotFound, "Process not found.")
+		return
+	} else if err != nil {
+		logger.WithError(err).WithField("process_id", id).Error("Failed to list queues.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to list queues.")
+		return
+	}
+
+	writeJSON(ctx, map[string]interface{}{"queues": queues})
+}
+
+// queueParam returns the CCB id of the queue in the "queue" route parameter, which
+// is a queue name configured in CCB_QUEUES or a queue id. If it returns false, an
+// error has been written.
+func queueParam(ctx iris.Context) (string, bool) {
+	id, ok := getCCBConfig().QueueID(ctx.Params().Get("queue"))
+	if !ok {
+		httperr.Write(ctx, http.StatusNotFound, "Queue not found.")
+	}
+	return id, ok
+}
+
+// queueIndividualsGet handles the GET route for the individuals in a queue.
+func queueIndividualsGet(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	queueID, ok := queueParam(ctx)
+	if !ok {
+		return
+	}
+
+	individuals, err := getCCBService().ListQueueIndividuals(ctx.Request().Context(), queueID)
+	if err == ccb.ErrNotFound {
+		httperr.Write(ctx, http.StatusNotFound, "Queue not found.")
+		return
+	} else if err != nil {
+		logger.WithError(err).WithField("queue_id", queueID).Error("Failed to list queue individuals.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to list queue individuals.")
+		return
+	}
+
+	writeJSON(ctx, map[string]interface{}{"individuals": individuals})
+}
+
+// queueIndividualAddRequest is the JSON body of a request to add an individual to
+// a queue. It takes either the individual id, or the details of the person as
+// given on a form, who is then matched to an individual in CCB or created.
+type queueIndividualAddRequest struct {
+	IndividualID string `json:"individual_id"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+	CampusID     string `json:"campus_id"` // Campus to create the individual in.
+
+	ManagerID string `json:"manager_id"`
+	Note      string `json:"note"`
+	DueDate   string `json:"due_date"` // Date, e.g. 2019-11-03.
+}
+
+// queueIndividualAdd handles the POST route for adding an individual to a queue.
+// it takes a JSON body of queueIndividualAddRequest.
+// returns the id of the individual in JSON format, and whether it was created
+func queueIndividualAdd(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	queueID, ok := queueParam(ctx)
+	if !ok {
+		return
+	}
+	logger = logger.WithField("queue_id", queueID)
+
+	var req queueIndividualAddRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+	dueDate, err := parseDateParam(req.DueDate)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid due date.")
+		return
+	}
+
+	individualID := strings.TrimSpace(req.IndividualID)
+	created := false
+	if individualID == "" {
+		details, ok := parseIndividualDetails(ctx, req.FirstName, req.LastName, req.Email, req.Phone)
+		if !ok {
+			return
+		}
+		details.CampusID = strings.TrimSpace(req.CampusID)
+
+		individual, c, err := ccb.MatchOrCreateIndividual(ctx.Request().Context(), getCCBService(), details)
+		if err != nil {
+			logger.WithError(err).Error("Failed to match or create individual.")
+			httperr.Write(ctx, http.StatusInternalServerError, "Failed to add individual to queue.")
+			return
+		}
+		individualID, created = individual.ID, c
+	}
+	logger = logger.WithFields(logrus.Fields{
+		"individual_id":      individualID,
+		"individual_created": created,
+	})
+
+	if err := getCCBService().AddIndividualToQueue(ctx.Request().Context(), ccb.AddIndividualToQueueRequest{
+		QueueID:      queueID,
+		IndividualID: individualID,
+		ManagerID:    strings.TrimSpace(req.ManagerID),
+		Note:         strings.TrimSpace(req.Note),
+		DueDate:      dueDate,
+	}); err != nil {
+		logger.WithError(err).Error("Failed to add individual to queue.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to add individual to queue.")
+		return
+	}
+
+	logger.Info("Added individual to queue.")
+	writeJSON(ctx, map[string]interface{}{
+		"individual_id":      individualID,
+		"individual_created": created,
+	})
+}
+
+// queueIndividualUpdateRequest is the JSON body of a request to update an
+// individual in a queue. Empty fields are left unchanged.
+type queueIndividualUpdateRequest struct {
+	Status    ccb.QueueStatus `json:"status"` // not_started, in_progress or done.
+	ManagerID string          `json:"manager_id"`
+	Note      string          `json:"note"`
+	DueDate   string          `json:"due_date"` // Date, e.g. 2019-11-03.
+}
+
+// queueIndividualUpdate handles the PUT route for updating an individual in a
+// queue, such as marking their follow-up as done.
+func queueIndividualUpdate(ctx iris.Context) {
+	logger := vouslog.GetLogger(ctx.Request().Context())
+	queueID, ok := queueParam(ctx)
+	if !ok {
+		return
+	}
+	individualID := ctx.Params().Get("individual_id")
+
+	var req queueIndividualUpdateRequest
+	if err := ctx.ReadJSON(&req); err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+	switch req.Status {
+	case "", ccb.QueueNotStarted, ccb.QueueInProgress, ccb.QueueDone:
+	default:
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid status, must be not_started, in_progress or done.")
+		return
+	}
+	dueDate, err := parseDateParam(req.DueDate)
+	if err != nil {
+		httperr.Write(ctx, http.StatusBadRequest, "Invalid due date.")
+		return
+	}
+
+	if err := getCCBService().ManageQueueIndividual(ctx.Request().Context(), ccb.ManageQueueIndividualRequest{
+		QueueID:      queueID,
+		IndividualID: individualID,
+		Status:       req.Status,
+		ManagerID:    strings.TrimSpace(req.ManagerID),
+		Note:         strings.TrimSpace(req.Note),
+		DueDate:      dueDate,
+	}); err != nil {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"queue_id":      queueID,
+			"individual_id": individualID,
+		}).Error("Failed to update queue individual.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to update queue individual.")
+		return
+	}
+
+	ctx.StatusCode(http.StatusNoContent)
+}
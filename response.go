@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	iris "github.com/kataras/iris/v12"
+	"github.com/mruVOUS/ccb-webflow-api/lib/httperr"
+	"github.com/mruVOUS/ccb-webflow-api/lib/vouslog"
+)
+
+// writeJSON writes v as a JSON response, with status 200 unless another status
+// has been set.
+func writeJSON(ctx iris.Context, v interface{}) {
+	out, err := json.Marshal(v)
+	if err != nil {
+		vouslog.GetLogger(ctx.Request().Context()).WithError(err).Error("Failed to marshal response.")
+		httperr.Write(ctx, http.StatusInternalServerError, "Failed to marshal response.")
+		return
+	}
+
+	ctx.ContentType("application/json")
+	ctx.Write(out)
+}
+
+// allowCORS returns a handler allowing cross-origin requests from the origin, or
+// from any origin if empty, so the website can call public routes from the
+// browser. Preflight OPTIONS requests are answered without calling the route.
+func allowCORS(origin string) iris.Handler {
+	if origin == "" {
+		origin = "*"
+	}
+	return func(ctx iris.Context) {
+		ctx.Header("Access-Control-Allow-Origin", origin)
+		ctx.Header("Access-Control-Allow-Methods", "GET, POST")
+		ctx.Header("Access-Control-Allow-Headers", "Content-Type")
+		if ctx.Method() == http.MethodOptions {
+			ctx.StatusCode(http.StatusNoContent)
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	iris "github.com/kataras/iris/v12"
+	"github.com/sirupsen/logrus"
+)
+
+// serverConfig holds configuration for the HTTP server.
+type serverConfig struct {
+	Port            int           `envconfig:"PORT"                    default:"8080"` // Set by Heroku.
+	ReadTimeout     time.Duration `envconfig:"SERVER_READ_TIMEOUT"     default:"15s"`  // Max time to read the request, including the body.
+	WriteTimeout    time.Duration `envconfig:"SERVER_WRITE_TIMEOUT"    default:"60s"`  // Max time to write the response. Exports use EXPORT_WRITE_TIMEOUT instead.
+	IdleTimeout     time.Duration `envconfig:"SERVER_IDLE_TIMEOUT"     default:"120s"` // Max time to keep idle keep-alive connections.
+	ShutdownTimeout time.Duration `envconfig:"SERVER_SHUTDOWN_TIMEOUT" default:"25s"`  // Heroku kills the dyno 30s after SIGTERM.
+
+	// TLS is optional and only needed when not hosted behind the Heroku router.
+	// Cert files take precedence over autocert.
+	TLSCertFile      string   `envconfig:"TLS_CERT_FILE"`
+	TLSKeyFile       string   `envconfig:"TLS_KEY_FILE"`
+	AutocertDomains  []string `envconfig:"AUTOCERT_DOMAINS"`                       // Comma separated whitelist of domains.
+	AutocertEmail    string   `envconfig:"AUTOCERT_EMAIL"`                         // Contact email for Let's Encrypt.
+	AutocertCacheDir string   `envconfig:"AUTOCERT_CACHE_DIR" default:"letscache"` // Where obtained certificates are cached.
+}
+
+// addr returns the address the server listens on.
+func (cfg serverConfig) addr() string {
+	return ":" + strconv.Itoa(cfg.Port)
+}
+
+// newHTTPServer creates the http.Server with the configured timeouts. Its
+// connections are tracked so handlers can extend their write deadline.
+func (cfg serverConfig) newHTTPServer() *http.Server {
+	return &http.Server{
+		Addr:         cfg.addr(),
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+		ConnState:    serverConns.track,
+	}
+}
+
+// connTracker keeps the open connections of the server by remote address, which
+// is the RemoteAddr of their requests.
+type connTracker struct {
+	mu    sync.Mutex
+	conns map[string]net.Conn
+}
+
+// serverConns are the connections of the server.
+var serverConns = &connTracker{conns: map[string]net.Conn{}}
+
+func (t *connTracker) track(c net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch state {
+	case http.StateNew:
+		t.conns[c.RemoteAddr().String()] = c
+	case http.StateHijacked, http.StateClosed:
+		delete(t.conns, c.RemoteAddr().String())
+	}
+}
+
+// extendWriteDeadline replaces the server write timeout for the response to req,
+// for responses which take longer to write, such as exports. The server sets the
+// deadline when it reads the request, so the handler's deadline takes its place.
+func extendWriteDeadline(req *http.Request, deadline time.Time) error {
+	serverConns.mu.Lock()
+	c, ok := serverConns.conns[req.RemoteAddr]
+	serverConns.mu.Unlock()
+	if !ok {
+		return errors.New("no connection for " + req.RemoteAddr)
+	}
+	return c.SetWriteDeadline(deadline)
+}
+
+// runner returns the iris runner to listen with, choosing TLS when configured.
+func (cfg serverConfig) runner(srv *http.Server) iris.Runner {
+	return func(app *iris.Application) error {
+		su := app.NewHost(srv)
+		switch {
+		case cfg.TLSCertFile != "" || cfg.TLSKeyFile != "":
+			return su.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		case len(cfg.AutocertDomains) > 0:
+			return su.ListenAndServeAutoTLS(strings.Join(cfg.AutocertDomains, " "), cfg.AutocertEmail, cfg.AutocertCacheDir)
+		default:
+			return su.ListenAndServe()
+		}
+	}
+}
+
+// tlsMode describes how the server terminates TLS, for logging.
+func (cfg serverConfig) tlsMode() string {
+	switch {
+	case cfg.TLSCertFile != "" || cfg.TLSKeyFile != "":
+		return "cert_file"
+	case len(cfg.AutocertDomains) > 0:
+		return "autocert"
+	default:
+		return "none"
+	}
+}
+
+// runServer starts the app and blocks until it has stopped. On SIGTERM or SIGINT
+// it stops accepting connections, then waits for in-flight requests and background
+// jobs to finish within the configured shutdown timeout.
+func runServer(app *iris.Application, cfg serverConfig, jobs *backgroundJobs) error {
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGTERM, os.Interrupt)
+		logrus.WithField("signal", (<-sig).String()).Info("Shutting down server.")
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+
+		if err := app.Shutdown(ctx); err != nil {
+			logrus.WithError(err).Error("Failed to drain in-flight requests.")
+		}
+		if err := jobs.Wait(ctx); err != nil {
+			logrus.WithError(err).Error("Failed to drain background jobs.")
+		}
+	}()
+
+	logrus.WithFields(logrus.Fields{
+		"port":          cfg.Port,
+		"tls":           cfg.tlsMode(),
+		"read_timeout":  cfg.ReadTimeout.String(),
+		"write_timeout": cfg.WriteTimeout.String(),
+		"idle_timeout":  cfg.IdleTimeout.String(),
+	}).Info("Starting server.")
+
+	err := app.Run(cfg.runner(cfg.newHTTPServer()),
+		iris.WithoutInterruptHandler,
+		iris.WithoutServerError(iris.ErrServerClosed),
+	)
+	if err != nil {
+		return err
+	}
+
+	// The listener only closes once shutdown has started, so wait for the drain.
+	<-shutdownDone
+	logrus.Info("Server stopped.")
+	return nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mruVOUS/ccb-webflow-api/lib/cache"
+	"github.com/mruVOUS/ccb-webflow-api/lib/captcha"
+	"github.com/mruVOUS/ccb-webflow-api/lib/ccb"
+	"github.com/mruVOUS/ccb-webflow-api/lib/notify"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ccbServiceOnce sync.Once
+	ccbConfig      ccb.Config
+	ccbService     ccb.Service
+)
+
+// getCCBService returns the CCB service shared by all handlers, configured from
+// the environment on first use.
+func getCCBService() ccb.Service {
+	ccbServiceOnce.Do(func() {
+		envconfig.MustProcess("", &ccbConfig)
+		if err := ccbConfig.FormsConfig.Validate(); err != nil {
+			logrus.WithError(err).Fatal("Invalid forms config.")
+		}
+		if len(ccbConfig.CampusSlugs) == 0 {
+			logrus.Warn("CCB_CAMPUS_SLUGS is not set, so form campuses may not match the campuses of groups and events.")
+		}
+		ccbService = ccb.New(ccbConfig)
+	})
+	return ccbService
+}
+
+// getCCBConfig returns the config of the CCB service.
+func getCCBConfig() ccb.Config {
+	getCCBService()
+	return ccbConfig
+}
+
+// getLocation returns the time zone of the church, which dates in query
+// parameters are in.
+func getLocation() *time.Location {
+	return getCCBConfig().Location()
+}
+
+// getForms returns the forms configured for the CCB service.
+func getForms() ccb.Forms {
+	return getCCBConfig().Forms()
+}
+
+// cacheConfig configures how long slow to compute responses are cached for.
+type cacheConfig struct {
+	StatsTTL        time.Duration `envconfig:"STATS_CACHE_TTL"         default:"10m"` // Form response and giving statistics reports.
+	PublicGroupsTTL time.Duration `envconfig:"PUBLIC_GROUPS_CACHE_TTL" default:"15m"` // Groups listed on the website.
+	PublicEventsTTL time.Duration `envconfig:"PUBLIC_EVENTS_CACHE_TTL" default:"15m"` // Events on the website calendar.
+}
+
+var (
+	cachesOnce        sync.Once
+	statsCache        *cache.Cache
+	publicGroupsCache *cache.Cache
+	publicEventsCache *cache.Cache
+)
+
+func loadCaches() {
+	cachesOnce.Do(func() {
+		cfg := cacheConfig{}
+		envconfig.MustProcess("", &cfg)
+		statsCache = cache.New(cfg.StatsTTL)
+		publicGroupsCache = cache.New(cfg.PublicGroupsTTL)
+		publicEventsCache = cache.New(cfg.PublicEventsTTL)
+	})
+}
+
+// getStatsCache returns the cache of form response and giving statistics reports.
+func getStatsCache() *cache.Cache {
+	loadCaches()
+	return statsCache
+}
+
+// getPublicGroupsCache returns the cache of groups listed on the website.
+func getPublicGroupsCache() *cache.Cache {
+	loadCaches()
+	return publicGroupsCache
+}
+
+// getPublicEventsCache returns the cache of events on the website calendar.
+func getPublicEventsCache() *cache.Cache {
+	loadCaches()
+	return publicEventsCache
+}
+
+// exportConfig configures form response exports.
+type exportConfig struct {
+	// WriteTimeout replaces the server write timeout for exports, which page through
+	// every response of the form in CCB while writing.
+	WriteTimeout time.Duration `envconfig:"EXPORT_WRITE_TIMEOUT" default:"10m"`
+}
+
+var (
+	exportCfgOnce sync.Once
+	exportCfg     exportConfig
+)
+
+// getExportConfig returns the config of form response exports.
+func getExportConfig() exportConfig {
+	exportCfgOnce.Do(func() {
+		envconfig.MustProcess("", &exportCfg)
+	})
+	return exportCfg
+}
+
+var (
+	notifierOnce sync.Once
+	notifier     notify.Notifier
+)
+
+// getNotifier returns the notifier for the configured notification channel.
+func getNotifier() notify.Notifier {
+	notifierOnce.Do(func() {
+		cfg := notify.Config{}
+		envconfig.MustProcess("", &cfg)
+		n, err := notify.New(cfg)
+		if err != nil {
+			logrus.WithError(err).Fatal("Invalid notification config.")
+		}
+		notifier = n
+	})
+	return notifier
+}
+
+// groupJoinConfig limits requests to join groups from the website, which create
+// individuals and group requests in CCB.
+type groupJoinConfig struct {
+	RateLimit  int           `envconfig:"GROUP_JOIN_RATE_LIMIT"  default:"5"` // Requests allowed from each client IP per window.
+	RateWindow time.Duration `envconfig:"GROUP_JOIN_RATE_WINDOW" default:"1h"`
+}
+
+var (
+	groupJoinCfgOnce sync.Once
+	groupJoinCfg     groupJoinConfig
+)
+
+// getGroupJoinConfig returns the limits of requests to join groups.
+func getGroupJoinConfig() groupJoinConfig {
+	groupJoinCfgOnce.Do(func() {
+		envconfig.MustProcess("", &groupJoinCfg)
+	})
+	return groupJoinCfg
+}
+
+var (
+	captchaOnce     sync.Once
+	captchaVerifier *captcha.Verifier
+)
+
+// getCaptcha returns the verifier of CAPTCHA tokens from the website, or nil if no
+// CAPTCHA is configured.
+func getCaptcha() *captcha.Verifier {
+	captchaOnce.Do(func() {
+		cfg := captcha.Config{}
+		envconfig.MustProcess("", &cfg)
+		v, err := captcha.New(cfg)
+		if err != nil {
+			logrus.WithError(err).Fatal("Invalid CAPTCHA config.")
+		}
+		captchaVerifier = v
+	})
+	return captchaVerifier
+}